@@ -0,0 +1,86 @@
+package webui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// eventArgs provides the JavaScript arguments of an event. Events received from WebUI read
+// them with `webuiArgs`.
+type eventArgs interface {
+	count() uint
+	size(idx uint) uint
+	raw(idx uint) []byte
+	str(idx uint) string
+	integer(idx uint) int
+	float(idx uint) float64
+	boolean(idx uint) bool
+}
+
+// arguments returns the source of the event's JavaScript arguments.
+func (e Event) arguments() eventArgs {
+	if e.args == nil {
+		return webuiArgs(e)
+	}
+	return e.args
+}
+
+// storedArgs are JavaScript arguments held in Go, e.g., of events dispatched by mock windows.
+// They are converted like WebUI's getters do.
+type storedArgs []string
+
+func (a storedArgs) count() uint {
+	return uint(len(a))
+}
+
+func (a storedArgs) size(idx uint) uint {
+	return uint(len(a.str(idx)))
+}
+
+func (a storedArgs) raw(idx uint) []byte {
+	if s := a.str(idx); s != "" {
+		return []byte(s)
+	}
+	return nil
+}
+
+func (a storedArgs) str(idx uint) string {
+	if idx >= uint(len(a)) {
+		return ""
+	}
+	return a[idx]
+}
+
+// integer parses the argument like WebUI's integer getter, which truncates decimals.
+func (a storedArgs) integer(idx uint) int {
+	raw := strings.TrimSpace(a.str(idx))
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return int(n)
+	}
+	f, _ := strconv.ParseFloat(raw, 64)
+	return int(f)
+}
+
+func (a storedArgs) float(idx uint) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(a.str(idx)), 64)
+	return f
+}
+
+func (a storedArgs) boolean(idx uint) bool {
+	return a.str(idx) == "true"
+}
+
+// storeArgs returns the event with its JavaScript arguments held in Go, so that they can be
+// read after WebUI released them.
+func (e Event) storeArgs() Event {
+	if e.args != nil {
+		return e
+	}
+	args := e.arguments()
+	stored := make(storedArgs, args.count())
+	for i := range stored {
+		stored[i] = string(args.raw(uint(i)))
+	}
+	e.args = stored
+	return e
+}
@@ -0,0 +1,142 @@
+package webui
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	eventType = reflect.TypeOf(Event{})
+)
+
+// BindFunc binds a Go function so that JavaScript can call it by the name `element`.
+// The JavaScript arguments are decoded into the function's parameters: strings are passed
+// as-is, but reject JavaScript objects and arrays, and other types are decoded from JSON. A leading `Event` parameter receives the event
+// itself. The function can return a result, an error, or a result and an error. The result
+// is encoded as JSON and returned to JavaScript. A non-nil error makes the JavaScript call
+// reject, like with `BindWithError`.
+func (w Window) BindFunc(element string, fn any) error {
+	callback, err := funcCallback(element, fn)
+	if err != nil {
+		return err
+	}
+	w.Bind(element, callback)
+	w.setFuncType(element, reflect.TypeOf(fn))
+	return nil
+}
+
+// BindStruct binds all exported methods of `v` using BindFunc. Each method is bound as
+// `name.Method` and can be called from JavaScript as `webui.call('name.Method', ...args)`.
+// Nothing is bound if any of the methods has an unsupported signature.
+func (w Window) BindStruct(name string, v any) error {
+	if v == nil {
+		return fmt.Errorf("error: failed to bind `%s`: the value is nil", name)
+	}
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+	if rt.NumMethod() == 0 {
+		return fmt.Errorf("error: failed to bind `%s`: `%s` has no exported methods", name, rt)
+	}
+	callbacks := make(map[string]func(Event) any, rt.NumMethod())
+	for i := 0; i < rt.NumMethod(); i++ {
+		element := name + "." + rt.Method(i).Name
+		callback, err := funcCallback(element, rv.Method(i).Interface())
+		if err != nil {
+			return err
+		}
+		callbacks[element] = callback
+	}
+	for element, callback := range callbacks {
+		w.Bind(element, callback)
+	}
+	for i := 0; i < rt.NumMethod(); i++ {
+		w.setFuncType(name+"."+rt.Method(i).Name, rv.Method(i).Type())
+	}
+	return nil
+}
+
+func (w Window) setFuncType(element string, t reflect.Type) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.funcTypes[element] = t
+}
+
+// funcCallback wraps the function `fn` into a callback that decodes its arguments
+// from the event and returns its result.
+func funcCallback(element string, fn any) (func(Event) any, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return nil, fmt.Errorf("error: failed to bind `%s`: expected a function, got `%T`", element, fn)
+	}
+	if fv.IsNil() {
+		return nil, fmt.Errorf("error: failed to bind `%s`: the function is nil", element)
+	}
+	ft := fv.Type()
+	if ft.IsVariadic() {
+		return nil, fmt.Errorf("error: failed to bind `%s`: variadic functions are not supported", element)
+	}
+	switch {
+	case ft.NumOut() > 2,
+		ft.NumOut() == 2 && ft.Out(1) != errorType:
+		return nil, fmt.Errorf("error: failed to bind `%s`: expected the results `(T)`, `(error)` or `(T, error)`", element)
+	}
+	withEvent := ft.NumIn() > 0 && ft.In(0) == eventType
+
+	return func(e Event) any {
+		args := make([]reflect.Value, ft.NumIn())
+		jsIdx := uint(0)
+		for i := range args {
+			if i == 0 && withEvent {
+				args[i] = reflect.ValueOf(e)
+				continue
+			}
+			arg := reflect.New(ft.In(i))
+			if err := decodeArg(e, jsIdx, arg.Interface()); err != nil {
+				return &callbackError{err}
+			}
+			args[i] = arg.Elem()
+			jsIdx++
+		}
+		return funcResult(fv.Call(args))
+	}, nil
+}
+
+// decodeArg decodes the JavaScript argument at the index `idx` into the value `p` points to.
+func decodeArg(e Event, idx uint, p any) error {
+	if idx >= e.ArgCount() {
+		return &noArgError{e.Element}
+	}
+	raw := e.GetStringAt(idx)
+	if s, ok := p.(*string); ok {
+		if err := checkArgKind(s, raw); err != nil {
+			return &getArgError{err, e.Element, "string"}
+		}
+		*s = raw
+		return nil
+	}
+	if err := json.Unmarshal([]byte(raw), p); err != nil {
+		return &getArgError{err, e.Element, reflect.TypeOf(p).Elem().String()}
+	}
+	return nil
+}
+
+// funcResult returns the value that is sent to JavaScript for the results of a bound function.
+func funcResult(out []reflect.Value) any {
+	if len(out) == 0 {
+		return nil
+	}
+	last := out[len(out)-1]
+	if last.Type() == errorType {
+		if !last.IsNil() {
+			return &callbackError{last.Interface().(error)}
+		}
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out[0].Interface()
+}
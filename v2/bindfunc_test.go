@@ -0,0 +1,43 @@
+package webui
+
+import "testing"
+
+func TestBindFuncInvalid(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	var nilFunc func() string
+	tests := []struct {
+		name string
+		fn   any
+	}{
+		{"nil", nil},
+		{"nil function", nilFunc},
+		{"string", "greet"},
+		{"variadic", func(names ...string) string { return "" }},
+		{"non-error second result", func() (string, string) { return "", "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := w.BindFunc("greet", tt.fn); err == nil {
+				t.Errorf("BindFunc(%q, %T) returned no error", "greet", tt.fn)
+			}
+		})
+	}
+	if err := w.BindStruct("api", nil); err == nil {
+		t.Error(`BindStruct("api", nil) returned no error`)
+	}
+}
+
+func TestBindFuncArgKind(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	if err := w.BindFunc("greet", func(name string) string { return "Hello, " + name }); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := w.Call("greet", "Go"); err != nil || result != "Hello, Go" {
+		t.Errorf(`Call("greet", "Go") = %v, %v; want "Hello, Go", nil`, result, err)
+	}
+	if _, err := w.Call("greet", map[string]string{"name": "Go"}); err == nil {
+		t.Error(`Call("greet", object) returned no error`)
+	}
+}
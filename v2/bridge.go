@@ -0,0 +1,99 @@
+package webui
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	rootFolderMu sync.RWMutex
+	// Root folder of windows without their own, as set with `SetDefaultRootFolder`.
+	defaultRootFolder string
+)
+
+// Matches the script tag that loads WebUI's JavaScript bridge.
+var webuiScriptTag = regexp.MustCompile(`(?i)<script[^>]*\ssrc=["']?[^"'>]*webui\.js["']?[^>]*>\s*</script>`)
+
+// Matches the opening head tag.
+var headTag = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+
+// withBridge returns the HTML page with the Go side of the JavaScript bridge inserted after
+// WebUI's bridge `webui.js`. Pages that don't load `webui.js` get it inserted as well. The
+// script is part of the page, so that it is in place before the page calls Go.
+func withBridge(html string) string {
+	script := "<script>\n" + errorBridgeScript + "\n</script>"
+	if loc := webuiScriptTag.FindStringIndex(html); loc != nil {
+		return html[:loc[1]] + script + html[loc[1]:]
+	}
+	script = `<script src="/webui.js"></script>` + script
+	if loc := headTag.FindStringIndex(html); loc != nil {
+		return html[:loc[1]] + script + html[loc[1]:]
+	}
+	return script + html
+}
+
+// isHTML tells whether the content passed to `Show` is HTML rather than a file or URL.
+func isHTML(content string) bool {
+	return strings.Contains(strings.ToLower(content), "<html")
+}
+
+// setRootFolder records the root folder the window's HTML files are served from.
+func (w Window) setRootFolder(path string) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.rootFolder = path
+}
+
+// rootFolder returns the folder the window's files are served from.
+func (w Window) rootFolder() string {
+	st := w.state()
+	st.mu.RLock()
+	root := st.rootFolder
+	st.mu.RUnlock()
+	if root != "" {
+		return root
+	}
+	rootFolderMu.RLock()
+	defer rootFolderMu.RUnlock()
+	if defaultRootFolder != "" {
+		return defaultRootFolder
+	}
+	return "."
+}
+
+func setDefaultRootFolder(path string) {
+	rootFolderMu.Lock()
+	defer rootFolderMu.Unlock()
+	defaultRootFolder = path
+}
+
+// htmlFileHandler returns a handler that serves the HTML files in the folder `root`, so that
+// the bridge is inserted into them. Other files are left to WebUI.
+func htmlFileHandler(root string) http.Handler {
+	files := fsHandler(os.DirFS(root))
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			r.URL.Path += "index.html"
+		}
+		switch path.Ext(r.URL.Path) {
+		case ".html", ".htm":
+			files.ServeHTTP(rw, r)
+		default:
+			http.NotFound(rw, r)
+		}
+	})
+}
+
+// showContent returns the content to pass to WebUI's show functions, with the bridge
+// inserted into HTML.
+func showContent(content string) string {
+	if isHTML(content) {
+		return withBridge(content)
+	}
+	return content
+}
@@ -0,0 +1,93 @@
+package webui
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithBridge(t *testing.T) {
+	script := "<script>\n" + errorBridgeScript + "\n</script>"
+	loader := `<script src="/webui.js"></script>`
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			"after webui.js",
+			`<html><head><script src="webui.js"></script><title>a</title></head></html>`,
+			`<html><head><script src="webui.js"></script>` + script + `<title>a</title></head></html>`,
+		},
+		{
+			"without webui.js",
+			`<html><HEAD lang="en"><title>a</title></head></html>`,
+			`<html><HEAD lang="en">` + loader + script + `<title>a</title></head></html>`,
+		},
+		{
+			"without head",
+			`<html><body>a</body></html>`,
+			loader + script + `<html><body>a</body></html>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withBridge(tt.html); got != tt.want {
+				t.Errorf("withBridge(%q) = %q; want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeHTMLWithBridge(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<html><head><script src="/webui.js"></script></head></html>`)},
+		"app.js":     {Data: []byte(`console.log("<html>")`)},
+	}
+	for _, tt := range []struct {
+		name, url  string
+		wantBridge bool
+	}{
+		{"html", "/index.html", true},
+		{"script", "/app.js", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := serveFile(fsHandler(fsys), tt.url)
+			res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+			if got := strings.Contains(string(body), errorBridgeScript); got != tt.wantBridge {
+				t.Errorf("body of %s contains the bridge = %v; want %v", tt.url, got, tt.wantBridge)
+			}
+			if res.ContentLength != int64(len(body)) {
+				t.Errorf("Content-Length = %d; want %d", res.ContentLength, len(body))
+			}
+		})
+	}
+}
+
+func TestHTMLFileHandler(t *testing.T) {
+	dir := t.TempDir()
+	h := htmlFileHandler(dir)
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "style.css"), []byte("body {}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if serveFile(h, "/") == nil {
+		t.Error(`serveFile("/") = nil; want index.html`)
+	}
+	if raw := serveFile(h, "/style.css"); raw != nil {
+		t.Errorf(`serveFile("/style.css") = %q; want nil`, raw)
+	}
+}
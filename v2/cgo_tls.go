@@ -14,6 +14,8 @@ import "C"
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"unsafe"
 )
 
@@ -30,3 +32,16 @@ func SetTLSCertificate(certificate_pem string, private_key_pem string) (err erro
 	}
 	return
 }
+
+// SetTLSCertificateFiles sets the SSL/TLS certificate and the private key from PEM files.
+func SetTLSCertificateFiles(certificate_file string, private_key_file string) (err error) {
+	certificate_pem, err := os.ReadFile(certificate_file)
+	if err != nil {
+		return fmt.Errorf("error: failed to read TLS certificate: %w", err)
+	}
+	private_key_pem, err := os.ReadFile(private_key_file)
+	if err != nil {
+		return fmt.Errorf("error: failed to read TLS private key: %w", err)
+	}
+	return SetTLSCertificate(string(certificate_pem), string(private_key_pem))
+}
@@ -0,0 +1,84 @@
+package webui
+
+/*
+#cgo CFLAGS: -Iwebui/include
+#include "webui.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+// SetMultiClient determines whether multiple clients, e.g., several browser tabs or devices,
+// can connect to the same window. Events carry the `ClientID` of the client that sent them.
+func SetMultiClient(enable bool) {
+	C.webui_set_config(C.multi_client, C._Bool(enable))
+}
+
+// trackClient updates the connected clients of the event's window.
+func trackClient(e Event) {
+	st := e.Window.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if e.EventType == Disconnected {
+		delete(st.clients, e.ClientID)
+		return
+	}
+	st.clients[e.ClientID] = e.connectionId
+}
+
+// Clients returns the IDs of the clients connected to the window.
+func (w Window) Clients() []uint {
+	st := w.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	ids := make([]uint, 0, len(st.clients))
+	for id := range st.clients {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// clientEvent returns an event that addresses the client in C calls.
+func (w Window) clientEvent(clientID uint) (e Event, err error) {
+	st := w.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	connectionId, ok := st.clients[clientID]
+	if !ok {
+		err = fmt.Errorf("error: client %d is not connected to window %d", clientID, w)
+		return
+	}
+	return Event{Window: w, ClientID: clientID, connectionId: connectionId}, nil
+}
+
+// RunClient executes JavaScript in a single client without waiting for the response.
+func (w Window) RunClient(clientID uint, script string) error {
+	e, err := w.clientEvent(clientID)
+	if err != nil {
+		return err
+	}
+	cEvent := e.cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	cscript := C.CString(script)
+	defer C.free(unsafe.Pointer(cscript))
+	C.webui_run_client(cEvent, cscript)
+	countBytesSent(w, len(script))
+	return nil
+}
+
+// CloseClient closes the connection of a single client.
+func (w Window) CloseClient(clientID uint) error {
+	e, err := w.clientEvent(clientID)
+	if err != nil {
+		return err
+	}
+	cEvent := e.cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	C.webui_close_client(cEvent)
+	return nil
+}
@@ -0,0 +1,38 @@
+package webui
+
+/*
+#cgo CFLAGS: -Iwebui/include
+#include "webui.h"
+
+extern bool goWebuiCloseHandler(size_t window);
+static void go_webui_set_close_handler(size_t win) {
+	webui_set_close_handler_wv(win, goWebuiCloseHandler);
+}
+*/
+import "C"
+
+// OnClose sets a function that is called when the user tries to close the window, before it
+// disconnects. Returning false keeps the window open, e.g., to ask about unsaved changes first.
+// Closing can only be intercepted for windows shown in a WebView (see `ShowWebView`); web
+// browsers don't allow to veto closing a tab or window.
+func (w Window) OnClose(handler func() bool) {
+	st := w.state()
+	st.mu.Lock()
+	st.closeHandler = handler
+	st.mu.Unlock()
+	C.go_webui_set_close_handler(C.size_t(w))
+}
+
+// Private function that receives close requests of WebView windows.
+//
+//export goWebuiCloseHandler
+func goWebuiCloseHandler(window C.size_t) C._Bool {
+	st := Window(window).state()
+	st.mu.RLock()
+	handler := st.closeHandler
+	st.mu.RUnlock()
+	if handler == nil {
+		return true
+	}
+	return C._Bool(handler())
+}
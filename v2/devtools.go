@@ -0,0 +1,272 @@
+package webui
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Time a DevTools command may take, including connecting to the web browser.
+	devToolsTimeout = 30 * time.Second
+	// Maximum size of a DevTools message, e.g., a screenshot or PDF document encoded as base64.
+	devToolsMaxMessageSize = 256 << 20
+)
+
+// PDFOptions configures `PrintToPDF`. Zero values use the web browser's defaults.
+type PDFOptions struct {
+	Landscape       bool    `json:"landscape,omitempty"`
+	PrintBackground bool    `json:"printBackground,omitempty"`
+	Scale           float64 `json:"scale,omitempty"`
+	// Paper size in inches.
+	PaperWidth  float64 `json:"paperWidth,omitempty"`
+	PaperHeight float64 `json:"paperHeight,omitempty"`
+	// Margins in inches.
+	MarginTop    float64 `json:"marginTop,omitempty"`
+	MarginBottom float64 `json:"marginBottom,omitempty"`
+	MarginLeft   float64 `json:"marginLeft,omitempty"`
+	MarginRight  float64 `json:"marginRight,omitempty"`
+}
+
+// SetDevToolsPort starts the window's web browser with the Chrome DevTools Protocol enabled
+// on `port`, which is required by `CaptureScreenshot` and `PrintToPDF`. Needs to be called
+// before `Show()`, and only works with Chromium-based web browsers. A port of `0` disables it.
+// The port is accessible to all local processes.
+func (w Window) SetDevToolsPort(port uint) {
+	st := w.state()
+	st.mu.Lock()
+	st.devToolsPort = port
+	st.mu.Unlock()
+	w.applyBrowserArgs()
+}
+
+// CaptureScreenshot returns a PNG image of the page currently shown in the window.
+// It requires `SetDevToolsPort`.
+func (w Window) CaptureScreenshot() ([]byte, error) {
+	return w.devToolsData("Page.captureScreenshot", map[string]string{"format": "png"})
+}
+
+// PrintToPDF returns the page currently shown in the window as PDF document.
+// It requires `SetDevToolsPort`. Chromium-based web browsers only print to PDF in headless
+// mode, e.g., with `SetBrowserArgs("--headless=new")`.
+func (w Window) PrintToPDF(options PDFOptions) ([]byte, error) {
+	return w.devToolsData("Page.printToPDF", options)
+}
+
+// devToolsData runs a DevTools command that returns base64 encoded data and decodes it.
+func (w Window) devToolsData(method string, params any) ([]byte, error) {
+	var result struct {
+		Data string `json:"data"`
+	}
+	if err := w.devToolsCommand(method, params, &result); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(result.Data)
+}
+
+// devToolsCommand runs a DevTools command on the page of the window and decodes its result into `result`.
+func (w Window) devToolsCommand(method string, params any, result any) (err error) {
+	st := w.state()
+	st.mu.RLock()
+	port := st.devToolsPort
+	st.mu.RUnlock()
+	if port == 0 {
+		return fmt.Errorf("error: failed to run `%s`: DevTools are not enabled for window %d", method, w)
+	}
+	wsURL, err := w.devToolsTarget(port)
+	if err != nil {
+		return fmt.Errorf("error: failed to run `%s`: %v", method, err)
+	}
+	conn, err := dialWebSocket(wsURL)
+	if err != nil {
+		return fmt.Errorf("error: failed to run `%s`: %v", method, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(devToolsTimeout))
+
+	request, err := json.Marshal(map[string]any{"id": 1, "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	if err = conn.writeText(request); err != nil {
+		return fmt.Errorf("error: failed to run `%s`: %v", method, err)
+	}
+	for {
+		msg, err := conn.readMessage()
+		if err != nil {
+			return fmt.Errorf("error: failed to run `%s`: %v", method, err)
+		}
+		var response struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err = json.Unmarshal(msg, &response); err != nil || response.ID != 1 {
+			// Skip protocol events.
+			continue
+		}
+		if response.Error != nil {
+			return fmt.Errorf("error: failed to run `%s`: %s", method, response.Error.Message)
+		}
+		return json.Unmarshal(response.Result, result)
+	}
+}
+
+// devToolsTarget returns the DevTools WebSocket URL of the page that shows the window's URL.
+func (w Window) devToolsTarget(port uint) (string, error) {
+	windowURL := w.GetURL()
+	if windowURL == "" {
+		return "", errors.New("window is not shown")
+	}
+	client := http.Client{Timeout: devToolsTimeout}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/json/list", port))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var targets []struct {
+		Type                 string `json:"type"`
+		URL                  string `json:"url"`
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&targets); err != nil {
+		return "", err
+	}
+	for _, t := range targets {
+		if t.Type == "page" && strings.HasPrefix(t.URL, windowURL) && t.WebSocketDebuggerURL != "" {
+			return t.WebSocketDebuggerURL, nil
+		}
+	}
+	return "", fmt.Errorf("no page with URL %s found", windowURL)
+}
+
+// webSocketConn is a minimal client side WebSocket connection for DevTools commands.
+type webSocketConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// dialWebSocket opens a WebSocket connection to the `ws://` URL.
+func dialWebSocket(rawURL string) (*webSocketConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ws" {
+		return nil, fmt.Errorf("unsupported WebSocket URL %s", rawURL)
+	}
+	conn, err := net.DialTimeout("tcp", u.Host, devToolsTimeout)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes := make([]byte, 16)
+	if _, err = rand.Read(keyBytes); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+	conn.SetDeadline(time.Now().Add(devToolsTimeout))
+	_, err = fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", u.RequestURI(), u.Host, key)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	accept := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(accept[:]) {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed: %s", resp.Status)
+	}
+	return &webSocketConn{conn, r}, nil
+}
+
+// writeText sends a masked text frame.
+func (c *webSocketConn) writeText(payload []byte) error {
+	header := []byte{0x81}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, 0x80|byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 0x80|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 0x80|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return err
+	}
+	header = append(header, mask...)
+	masked := make([]byte, len(payload))
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+	_, err := c.Write(append(header, masked...))
+	return err
+}
+
+// readMessage returns the payload of the next text or binary message.
+// Control frames are skipped, except for close frames.
+func (c *webSocketConn) readMessage() (msg []byte, err error) {
+	for {
+		var header [2]byte
+		if _, err = io.ReadFull(c.r, header[:]); err != nil {
+			return nil, err
+		}
+		fin, opcode := header[0]&0x80 != 0, header[0]&0x0F
+		length := uint64(header[1] & 0x7F)
+		switch length {
+		case 126:
+			var ext [2]byte
+			if _, err = io.ReadFull(c.r, ext[:]); err != nil {
+				return nil, err
+			}
+			length = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err = io.ReadFull(c.r, ext[:]); err != nil {
+				return nil, err
+			}
+			length = binary.BigEndian.Uint64(ext[:])
+		}
+		if length > devToolsMaxMessageSize-uint64(len(msg)) {
+			return nil, fmt.Errorf("WebSocket message of more than %d bytes is too large", devToolsMaxMessageSize)
+		}
+		payload := make([]byte, length)
+		if _, err = io.ReadFull(c.r, payload); err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case 0x8:
+			return nil, errors.New("WebSocket connection closed")
+		case 0x9, 0xA:
+			continue
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
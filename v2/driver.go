@@ -0,0 +1,28 @@
+package webui
+
+// driver performs the window operations that depend on the UI. WebUI windows use
+// `webuiDriver`, mock windows dispatch the operations in Go.
+type driver interface {
+	bind(element string) uint
+	// unbind removes the binding of the element and returns its bind ID.
+	unbind(element string) uint
+	show(content string) bool
+	run(script string)
+	script(script string, options ScriptOptions) (string, error)
+	close()
+	destroy()
+	isShown() bool
+	// newWindow creates a window of the same kind.
+	newWindow() Window
+}
+
+// webuiDriver is the driver of a window implemented by WebUI.
+type webuiDriver Window
+
+// driver returns the driver of the window.
+func (w Window) driver() driver {
+	st := w.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.driver
+}
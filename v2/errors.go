@@ -0,0 +1,60 @@
+package webui
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Starts the response of a Go callback that failed, followed by the error message. JSON
+// responses never start with a control character.
+const callbackErrorPrefix = "\x15"
+
+// Makes calls to Go callbacks reject when they respond with an error.
+const errorBridgeScript = `if (typeof webui !== "undefined" && !webui.__goErrorBridge) {
+	webui.__goErrorBridge = true;
+	const call = webui.call.bind(webui);
+	webui.call = async (...args) => {
+		const resp = await call(...args);
+		if (typeof resp === "string" && resp.startsWith("\x15")) {
+			throw new Error(resp.slice(1));
+		}
+		return resp;
+	};
+}`
+
+// callbackError is the result of a callback that failed. It is passed to JavaScript as a rejection.
+type callbackError struct {
+	err error
+}
+
+func (e *callbackError) Error() string {
+	return e.err.Error()
+}
+
+// errorResponse returns the response that makes a JavaScript call reject with the error.
+func errorResponse(err error) []byte {
+	return []byte(callbackErrorPrefix + err.Error())
+}
+
+// callCallback calls the callback and recovers from a panic in it, returning the panic as error.
+func callCallback(callback func(Event) any, e Event) (result any) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := fmt.Errorf("`%s` panicked: %v", e.Element, r)
+			logf(LogError, "%v\n%s", err, debug.Stack())
+			result = &callbackError{err}
+		}
+	}()
+	return callback(e)
+}
+
+// callHook calls a hook and recovers from a panic in it, which is logged. Panics must not
+// unwind through WebUI's C code.
+func callHook(name string, hook func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logf(LogError, "%s panicked: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+	hook()
+}
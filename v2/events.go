@@ -0,0 +1,85 @@
+package webui
+
+import "sync"
+
+// Number of events the channels returned by `Events` buffer by default.
+const defaultEventBufferSize = 64
+
+type EventOptions struct {
+	// Number of events the channel buffers, 64 if zero.
+	BufferSize uint
+	// Whether events hold up the UI while the buffer is full, instead of being dropped.
+	Block bool
+}
+
+// eventSink is a channel returned by `Events`.
+type eventSink struct {
+	ch    chan Event
+	block bool
+	// Held for reading while sending, so that the channel isn't closed during a send.
+	mu     sync.RWMutex
+	closed bool
+	// Closed before the channel, to release blocked sends.
+	done chan struct{}
+	once sync.Once
+}
+
+func (s *eventSink) send(e Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	if s.block {
+		select {
+		case s.ch <- e:
+		case <-s.done:
+		}
+		return
+	}
+	select {
+	case s.ch <- e:
+	default:
+	}
+}
+
+// close closes the channel. Further events are discarded.
+func (s *eventSink) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.ch)
+	})
+}
+
+// Events returns a channel that receives all events of the window, as an alternative to
+// binding callbacks, e.g., to handle UI events in a `select` loop. Callbacks bound with
+// `Bind` still receive their events, and their results are still returned to JavaScript.
+// Events on the channel carry a copy of their JavaScript arguments, which can be read after
+// WebUI released the originals. Events that arrive while the buffer is full are dropped unless
+// `Block` is set. The channel is closed when the window is destroyed or `Exit` is called.
+func (w Window) Events(options EventOptions) <-chan Event {
+	size := options.BufferSize
+	if size == 0 {
+		size = defaultEventBufferSize
+	}
+	sink := &eventSink{ch: make(chan Event, size), block: options.Block, done: make(chan struct{})}
+	w.addHook(func(h *windowHooks) { h.sinks = append(h.sinks, sink) })
+	return sink.ch
+}
+
+// closeSinks closes the channels returned by `Events` for the window state.
+func (st *windowState) closeSinks() {
+	st.mu.Lock()
+	var sinks []*eventSink
+	if st.hooks != nil {
+		sinks = st.hooks.sinks
+		st.hooks.sinks = nil
+	}
+	st.mu.Unlock()
+	for _, sink := range sinks {
+		sink.close()
+	}
+}
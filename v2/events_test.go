@@ -0,0 +1,40 @@
+package webui
+
+import "testing"
+
+func TestEventsChannel(t *testing.T) {
+	w := NewMockWindow()
+	ch := w.Events(EventOptions{})
+	w.Bind("save", func(e Event) any { return nil })
+
+	// The buffer takes the events without a reader.
+	for i := 0; i < 3; i++ {
+		if _, err := w.Call("save", "a", 42); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		e := <-ch
+		if e.Element != "save" || e.GetString() != "a" || e.GetIntAt(1) != 42 {
+			t.Errorf("event = %q(%q, %d); want save(a, 42)", e.Element, e.GetString(), e.GetIntAt(1))
+		}
+	}
+
+	w.Close()
+	if _, ok := <-ch; ok {
+		t.Error("channel is open after the window was destroyed")
+	}
+}
+
+func TestEventsChannelDropsWhenFull(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	ch := w.Events(EventOptions{BufferSize: 1})
+	w.Bind("save", func(e Event) any { return nil })
+	for i := 0; i < 3; i++ {
+		w.Call("save")
+	}
+	if n := len(ch); n != 1 {
+		t.Errorf("buffered events = %d; want 1", n)
+	}
+}
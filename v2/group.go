@@ -0,0 +1,101 @@
+package webui
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Interval in which `WaitAll` checks whether the windows of a group are shown.
+const groupWaitInterval = 100 * time.Millisecond
+
+// WindowGroup is a set of windows that can be addressed at once. It is safe for concurrent use.
+type WindowGroup struct {
+	mu      sync.Mutex
+	windows []Window
+}
+
+// NewWindowGroup creates a group of the windows.
+func NewWindowGroup(windows ...Window) *WindowGroup {
+	g := &WindowGroup{}
+	g.Add(windows...)
+	return g
+}
+
+// Add adds the windows to the group. Windows already in the group are ignored.
+func (g *WindowGroup) Add(windows ...Window) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+outer:
+	for _, w := range windows {
+		for _, existing := range g.windows {
+			if existing == w {
+				continue outer
+			}
+		}
+		g.windows = append(g.windows, w)
+	}
+}
+
+// Remove removes the window from the group.
+func (g *WindowGroup) Remove(w Window) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, existing := range g.windows {
+		if existing == w {
+			g.windows = append(g.windows[:i:i], g.windows[i+1:]...)
+			return
+		}
+	}
+}
+
+// Windows returns the windows of the group in the order they were added.
+func (g *WindowGroup) Windows() []Window {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Window(nil), g.windows...)
+}
+
+// Run executes JavaScript in all windows of the group without waiting for the responses.
+func (g *WindowGroup) Run(script string) {
+	for _, w := range g.Windows() {
+		w.Run(script)
+	}
+}
+
+// Publish sends `payload` to the subscribers of `topic` in all windows of the group,
+// like `Window.Publish`.
+func (g *WindowGroup) Publish(topic string, payload any) error {
+	var errs []error
+	for _, w := range g.Windows() {
+		if err := w.Publish(topic, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseAll closes all windows of the group.
+func (g *WindowGroup) CloseAll() {
+	for _, w := range g.Windows() {
+		w.Close()
+	}
+}
+
+// WaitAll waits until none of the windows of the group is shown.
+// Unlike `Wait()`, windows outside the group may remain open.
+func (g *WindowGroup) WaitAll() {
+	for {
+		shown := false
+		for _, w := range g.Windows() {
+			if w.IsShown() {
+				shown = true
+				break
+			}
+		}
+		if !shown {
+			return
+		}
+		time.Sleep(groupWaitInterval)
+	}
+}
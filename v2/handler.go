@@ -0,0 +1,151 @@
+package webui
+
+/*
+#cgo CFLAGS: -Iwebui/include
+#include "webui.h"
+
+extern void* goWebuiFileHandler(size_t window, char* filename, int* length);
+static void go_webui_set_file_handler(size_t win) {
+	webui_set_file_handler_window(win, (const void* (*)(size_t, const char*, int*))goWebuiFileHandler);
+}
+*/
+import "C"
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unsafe"
+)
+
+// responseBuffer is a `http.ResponseWriter` that keeps the response in memory.
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+func (b *responseBuffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Private function that receives file requests of windows with a Go file handler.
+//
+//export goWebuiFileHandler
+func goWebuiFileHandler(window C.size_t, filename *C.char, length *C.int) unsafe.Pointer {
+	w := Window(window)
+	st := w.state()
+	st.mu.RLock()
+	h := st.fileHandler
+	st.mu.RUnlock()
+	if h == nil {
+		h = htmlFileHandler(w.rootFolder())
+	}
+	resp := serveFile(h, C.GoString(filename))
+	if resp == nil {
+		return nil
+	}
+	// WebUI frees the response after sending it.
+	ptr := C.webui_malloc(C.size_t(len(resp)))
+	copy(unsafe.Slice((*byte)(ptr), len(resp)), resp)
+	*length = C.int(len(resp))
+	return ptr
+}
+
+// serveFile passes a GET request for `url` to the handler and returns the raw HTTP response,
+// with the bridge inserted into HTML pages. It returns nil if the handler did not find the
+// file, so that WebUI can serve it instead.
+func serveFile(h http.Handler, url string) []byte {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+	req.RequestURI = url
+	rb := &responseBuffer{header: make(http.Header)}
+	h.ServeHTTP(rb, req)
+	if rb.status == 0 {
+		rb.status = http.StatusOK
+	}
+	if rb.status == http.StatusNotFound {
+		return nil
+	}
+	ctype := rb.header.Get("Content-Type")
+	if ctype == "" {
+		ctype = http.DetectContentType(rb.body.Bytes())
+	}
+	if strings.HasPrefix(ctype, "text/html") {
+		html := withBridge(rb.body.String())
+		rb.body.Reset()
+		rb.body.WriteString(html)
+	}
+	res := &http.Response{
+		StatusCode:    rb.status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rb.header,
+		ContentLength: int64(rb.body.Len()),
+		Body:          io.NopCloser(&rb.body),
+	}
+	var out bytes.Buffer
+	if err := res.Write(&out); err != nil {
+		return nil
+	}
+	return out.Bytes()
+}
+
+// SetHandler routes the window's file requests through the Go handler `h`, e.g., a
+// `http.ServeMux`. The handler receives GET requests carrying only the path of the requested
+// file; WebUI doesn't pass on the query string. Requests it answers with 404 Not Found are
+// served by WebUI as usual. A nil handler removes a previously set handler.
+func (w Window) SetHandler(h http.Handler) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.fileHandler = h
+}
+
+// setFileHandler routes the window's file requests through Go. Without a handler set with
+// `SetHandler`, Go serves the HTML files from the root folder and leaves other files to WebUI.
+func (w Window) setFileHandler() {
+	C.go_webui_set_file_handler(C.size_t(w))
+}
+
+// fsHandler returns a handler that serves the files of `fsys`.
+func fsHandler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			http.NotFound(rw, r)
+			return
+		}
+		ctype := mime.TypeByExtension(path.Ext(name))
+		if ctype == "" {
+			ctype = http.DetectContentType(data)
+		}
+		rw.Header().Set("Content-Type", ctype)
+		rw.Write(data)
+	})
+}
+
+// ShowFS opens a window that serves its files from `fsys`, e.g., an `embed.FS`, and shows the
+// file `entry`. Files that are not found in `fsys` are served by WebUI as usual.
+func (w Window) ShowFS(fsys fs.FS, entry string) error {
+	w.SetHandler(fsHandler(fsys))
+	return w.Show(entry)
+}
@@ -0,0 +1,39 @@
+package webui
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"testing/fstest"
+)
+
+func TestServeWasm(t *testing.T) {
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	h := fsHandler(fstest.MapFS{"a.wasm": {Data: wasm}})
+
+	raw := serveFile(h, "/a.wasm")
+	if raw == nil {
+		t.Fatal("serveFile() = nil; want a response")
+	}
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	// Required by `WebAssembly.instantiateStreaming`.
+	if ctype := res.Header.Get("Content-Type"); ctype != "application/wasm" {
+		t.Errorf("Content-Type = %q; want application/wasm", ctype)
+	}
+	if enc := res.Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q; want none", enc)
+	}
+	if body, _ := io.ReadAll(res.Body); !bytes.Equal(body, wasm) {
+		t.Errorf("body = %q; want %q", body, wasm)
+	}
+
+	if raw := serveFile(h, "/missing.wasm"); raw != nil {
+		t.Errorf("serveFile() of a missing file = %q; want nil", raw)
+	}
+}
@@ -0,0 +1,93 @@
+package webui
+
+// Lifecycle hooks registered for a window.
+type windowHooks struct {
+	connected    []func()
+	disconnected []func()
+	navigation   []func(url string)
+	navPolicy    func(url string) NavAction
+	// Channels returned by `Events`.
+	sinks []*eventSink
+	// Bind ID of the element that receives all events.
+	allEventsId uint
+}
+
+// OnConnected registers a function that is called when the UI connects to the window.
+// Lifecycle hooks make all events of the window handled in Go. WebUI then leaves link
+// navigation to Go, which performs it with `Navigate`, unless a callback bound to all events
+// or a navigation policy (see `SetNavigationPolicy`) handles it.
+func (w Window) OnConnected(hook func()) {
+	w.addHook(func(h *windowHooks) { h.connected = append(h.connected, hook) })
+}
+
+// OnDisconnected registers a function that is called when the UI disconnects from the window.
+func (w Window) OnDisconnected(hook func()) {
+	w.addHook(func(h *windowHooks) { h.disconnected = append(h.disconnected, hook) })
+}
+
+// OnNavigation registers a function that is called with the target URL when the UI navigates.
+func (w Window) OnNavigation(hook func(url string)) {
+	w.addHook(func(h *windowHooks) { h.navigation = append(h.navigation, hook) })
+}
+
+// addHook applies `add` to the hooks of the window. Hooks don't replace callbacks bound
+// to all events with `Bind("", ...)`; both are called.
+func (w Window) addHook(add func(h *windowHooks)) {
+	d := w.driver()
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.hooks == nil {
+		st.hooks = &windowHooks{}
+		// Receive lifecycle events without registering a callback.
+		st.hooks.allEventsId = d.bind("")
+		st.bindIds[""] = st.hooks.allEventsId
+	}
+	add(st.hooks)
+}
+
+// runHooks calls the lifecycle hooks of the event's window that match the event.
+func runHooks(e Event) {
+	st := e.Window.state()
+	st.mu.RLock()
+	h := st.hooks
+	if h == nil {
+		st.mu.RUnlock()
+		return
+	}
+	var (
+		simple     []func()
+		navigation []func(url string)
+		navPolicy  = h.navPolicy
+		sinks      []*eventSink
+	)
+	if e.bindId == h.allEventsId {
+		sinks = h.sinks
+	}
+	switch e.EventType {
+	case Connected:
+		simple = h.connected
+	case Disconnected:
+		simple = h.disconnected
+	case Navigation:
+		navigation = h.navigation
+	}
+	st.mu.RUnlock()
+	if len(sinks) > 0 {
+		// WebUI releases the arguments once the event is handled.
+		stored := e.storeArgs()
+		for _, sink := range sinks {
+			callHook("event channel", func() { sink.send(stored) })
+		}
+	}
+	for _, hook := range simple {
+		callHook("lifecycle hook", hook)
+	}
+	if e.EventType == Navigation {
+		url := e.GetString()
+		for _, hook := range navigation {
+			callHook("navigation hook", func() { hook(url) })
+		}
+		applyNavPolicy(e, url, navPolicy)
+	}
+}
@@ -18,16 +18,24 @@ extern void goWebuiEventHandler(webui_event_t* e);
 static size_t go_webui_bind(size_t win, const char* element) {
 	return webui_bind(win, element, goWebuiEventHandler);
 }
+static size_t go_webui_unbind(size_t win, const char* element) {
+	return webui_bind(win, element, NULL);
+}
 */
 import "C"
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -49,6 +57,7 @@ const (
 	Epic
 	Yandex
 	ChromiumBased
+	Webview
 )
 
 type Runtime uint8
@@ -70,11 +79,15 @@ const (
 )
 
 type Event struct {
-	Window      Window
-	EventType   EventType
-	Element     string
-	eventNumber uint
-	bindId      uint
+	Window       Window
+	EventType    EventType
+	Element      string
+	ClientID     uint
+	eventNumber  uint
+	bindId       uint
+	connectionId uint
+	// Arguments held in Go, nil for events whose arguments are read from WebUI.
+	args eventArgs
 }
 
 type ScriptOptions struct {
@@ -82,8 +95,22 @@ type ScriptOptions struct {
 	BufferSize uint
 }
 
+const (
+	defaultScriptBufferSize = 8 * 1024
+	maxScriptBufferSize     = 64 * 1024 * 1024
+	// Script timeout of `ScriptContext` for contexts without a deadline, in seconds.
+	defaultScriptContextTimeout = 30
+)
+
+type ScriptResult struct {
+	Response string
+	Err      error
+}
+
 type Void *struct{}
 
+type Data string
+
 type noArgError struct {
 	element string
 }
@@ -94,22 +121,26 @@ type getArgError struct {
 	typ     string
 }
 
-// User Go Callback Functions list
-var funcList = make(map[Window]map[uint]func(Event) any)
+var (
+	// Whether events are dispatched on their own goroutine.
+	concurrentEvents atomic.Bool
+)
 
 // == Definitions =============================================================
 
 // NewWindow creates a new WebUI window object and returns the window number.
 func NewWindow() Window {
 	w := Window(C.size_t(C.webui_new_window()))
-	funcList[w] = make(map[uint]func(Event) any)
+	w.resetState()
+	w.setFileHandler()
 	return w
 }
 
 // NewWindow creates a new webui window object using a specified window number.
 func (w Window) NewWindow() {
-	funcList[w] = make(map[uint]func(Event) any)
+	w.resetState()
 	C.webui_new_window_id(C.size_t(w))
+	w.setFileHandler()
 }
 
 // NewWindowId returns a free window number that can be used with `NewWindow`.
@@ -123,95 +154,435 @@ func NewWindowId() Window {
 func goWebuiEventHandler(e *C.webui_event_t) {
 	// Create Go event from C event.
 	goEvent := Event{
-		Window:      Window(e.window),
-		EventType:   EventType(e.event_type),
-		Element:     C.GoString(e.element),
-		eventNumber: uint(e.event_number),
-		bindId:      uint(e.bind_id),
+		Window:       Window(e.window),
+		EventType:    EventType(e.event_type),
+		Element:      C.GoString(e.element),
+		ClientID:     uint(e.client_id),
+		eventNumber:  uint(e.event_number),
+		bindId:       uint(e.bind_id),
+		connectionId: uint(e.connection_id),
+	}
+	primary, duplicate := goEvent.invocation()
+	if primary {
+		trackClient(goEvent)
+		countEvent(goEvent.Window)
+	}
+	async := concurrentEvents.Load()
+	// WebUI keeps the arguments of an event only until the handler returns, except for
+	// callback events with asynchronous responses, which wait for their response.
+	if async && goEvent.EventType == Callback && !duplicate {
+		go handleEvent(goEvent, true, true)
+		return
+	}
+	handleEvent(goEvent, async, !duplicate)
+}
+
+// invocation tells how the handler invocation for the event relates to the event. If an
+// element is bound and all events are bound too, WebUI invokes the handler for both bindings.
+// `primary` is true for exactly one invocation of each event. `duplicate` is true for the
+// invocation of the all-events binding if the element has its own binding, which answers it.
+func (e Event) invocation() (primary, duplicate bool) {
+	st := e.Window.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	allEventsId, ok := st.bindIds[""]
+	if !ok {
+		return true, false
+	}
+	if e.bindId != allEventsId {
+		return false, false
+	}
+	_, bound := st.bindIds[e.Element]
+	return true, e.Element != "" && bound
+}
+
+// handleEvent calls the user callback bound to the event and, with `respond`, passes its
+// result to JavaScript. With `async`, WebUI waits for a response to callback events even if
+// the result is empty.
+func handleEvent(e Event, async, respond bool) {
+	if e.EventType == Disconnected {
+		defer dropSession(e.Window, e.ClientID)
+	}
+	runHooks(e)
+	callback, ok := getCallback(e.Window, e.bindId)
+	if !ok {
+		return
 	}
 	// Call user callback function.
-	result := funcList[goEvent.Window][goEvent.bindId](goEvent)
-	if result == nil {
+	start := time.Now()
+	result := callCallback(e.Window.chain(callback), e)
+	recordLatency(e.Window, e.Element, time.Since(start))
+	if !respond {
 		return
 	}
-	response, err := json.Marshal(result)
-	if err != nil {
-		log.Println("error: failed to encode JS result into JSON", err)
+	var response []byte
+	if err, ok := result.(error); ok {
+		response = errorResponse(err)
+	} else if result != nil {
+		response = responseJSON(result)
+	} else if !async || e.EventType != Callback {
+		return
 	}
+	countBytesSent(e.Window, len(response))
 	cresponse := C.CString(string(response))
 	defer C.free(unsafe.Pointer(cresponse))
-	C.webui_interface_set_response(e.window, e.event_number, cresponse)
+	C.webui_interface_set_response(C.size_t(e.Window), C.size_t(e.eventNumber), cresponse)
+}
+
+// responseJSON encodes the result of a callback as JSON. Map keys are encoded in sorted order.
+func responseJSON(result any) []byte {
+	response, err := json.Marshal(result)
+	if err != nil {
+		logf(LogError, "failed to encode JS result into JSON: %v", err)
+	}
+	return response
+}
+
+// SetConcurrentEvents determines whether each event callback runs on its own goroutine.
+// When enabled, a slow callback does not hold up the handling of other events. Only callback
+// events, i.e., calls from JavaScript, are dispatched concurrently. Other events, e.g.,
+// connection and navigation events, are handled before WebUI continues.
+// It should be called before any window is shown.
+func SetConcurrentEvents(enable bool) {
+	concurrentEvents.Store(enable)
+	C.webui_set_config(C.asynchronous_response, C._Bool(enable))
+}
+
+func setCallback(w Window, funcId uint, callback func(Event) any) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.callbacks[funcId] = callback
+}
+
+func getCallback(w Window, funcId uint) (callback func(Event) any, ok bool) {
+	st := w.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	callback, ok = st.callbacks[funcId]
+	return
 }
 
 // Bind binds a specific html element click event with a function. Empty element means all events.
+// The value returned by the callback is sent to JavaScript encoded as JSON. Map keys are
+// encoded in sorted order, so responses built from maps are deterministic.
+// If the callback returns an error or panics, the JavaScript call rejects with the message.
+// Rejecting relies on a script that is part of the pages shown by Go (see `Show`).
 func (w Window) Bind(element string, callback func(Event) any) {
+	setCallback(w, w.bind(element), callback)
+}
+
+// Bind binds a specific html element click event with a function like `Window.Bind`.
+func Bind[T any](w Window, element string, callback func(Event) T) {
+	w.Bind(element, func(e Event) any {
+		return callback(e)
+	})
+}
+
+// BindWithError binds a specific html element click event with a function that returns a
+// result and an error. If the error is not nil, the JavaScript call rejects with its message,
+// so it can be handled with try/catch. Otherwise it resolves with the result.
+func BindWithError[T any](w Window, element string, callback func(Event) (T, error)) {
+	w.Bind(element, func(e Event) any {
+		result, err := callback(e)
+		if err != nil {
+			return err
+		}
+		return result
+	})
+}
+
+// bind binds the element to the Go event handler and returns its bind ID.
+func (w Window) bind(element string) uint {
+	funcId := w.driver().bind(element)
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.bindIds[element] = funcId
+	return funcId
+}
+
+func (d webuiDriver) bind(element string) uint {
 	celement := C.CString(element)
 	defer C.free(unsafe.Pointer(celement))
-	funcId := uint(C.go_webui_bind(C.size_t(w), celement))
-	funcList[w][funcId] = callback
+	return uint(C.go_webui_bind(C.size_t(d), celement))
 }
 
-// Bind binds a specific html element click event with a function. Empty element means all events.
-func Bind[T any](w Window, element string, callback func(Event) T) {
+func (d webuiDriver) unbind(element string) uint {
 	celement := C.CString(element)
 	defer C.free(unsafe.Pointer(celement))
-	funcId := uint(C.go_webui_bind(C.size_t(w), celement))
-	funcList[w][funcId] = func(e Event) any {
-		return callback(e)
+	return uint(C.go_webui_unbind(C.size_t(d), celement))
+}
+
+// Unbind removes the callback bound to the element. To replace a callback, it's enough to
+// bind the element again. Unbinding the empty element only removes the callback bound to all
+// events, lifecycle hooks and event channels keep receiving events.
+func (w Window) Unbind(element string) {
+	st := w.state()
+	st.mu.RLock()
+	h := st.hooks
+	st.mu.RUnlock()
+	var funcId uint
+	if element == "" && h != nil {
+		funcId = h.allEventsId
+	} else {
+		funcId = w.driver().unbind(element)
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.callbacks, funcId)
+	delete(st.funcTypes, element)
+	if element != "" || h == nil {
+		delete(st.bindIds, element)
 	}
 }
 
 // Show opens a window using embedded HTML, or a file. If the window is already open, it will be refreshed.
+// Go inserts a script into embedded HTML and into the HTML files it serves, which makes calls
+// of failing Go callbacks reject. Pages that don't load `webui.js` get it inserted too. HTML
+// files are served from the root folder (see `SetRootFolder`) or by the window's handler (see
+// `SetHandler`). Pages at other URLs don't get the script.
 func (w Window) Show(content string) (err error) {
-	ccontent := C.CString(content)
-	defer C.free(unsafe.Pointer(ccontent))
-	if !C.webui_show(C.size_t(w), ccontent) {
+	if !w.driver().show(content) {
 		err = errors.New("error: failed to show window")
+		return
 	}
+	setLastShown(w, shownContent{content, AnyBrowser})
 	return
 }
 
+func (d webuiDriver) show(content string) bool {
+	ccontent := C.CString(showContent(content))
+	defer C.free(unsafe.Pointer(ccontent))
+	return bool(C.webui_show(C.size_t(d), ccontent))
+}
+
 // ShowBrowser opens a window using embedded HTML, or a file in a specific web browser.
 // If the window is already open, it will be refreshed.
 func (w Window) ShowBrowser(content string, browser Browser) (err error) {
-	ccontent := C.CString(content)
+	ccontent := C.CString(showContent(content))
 	defer C.free(unsafe.Pointer(ccontent))
 	if !C.webui_show_browser(C.size_t(w), ccontent, C.size_t(browser)) {
 		err = errors.New("error: failed to show window")
+		return
 	}
+	setLastShown(w, shownContent{content, browser})
 	return
 }
 
+// ShowWebView opens a window using embedded HTML, or a file in the native WebView of the OS
+// (WebView2 on Windows, WebKitGTK on Linux, WKWebView on macOS) instead of a web browser.
+// If the window is already open, it will be refreshed.
+func (w Window) ShowWebView(content string) (err error) {
+	ccontent := C.CString(showContent(content))
+	defer C.free(unsafe.Pointer(ccontent))
+	if !C.webui_show_wv(C.size_t(w), ccontent) {
+		err = errors.New("error: failed to show WebView window")
+		return
+	}
+	setLastShown(w, shownContent{content, Webview})
+	return
+}
+
+// SetFrameless determines whether the WebView window is shown without a frame and title bar.
+func (w Window) SetFrameless(enable bool) {
+	C.webui_set_frameless(C.size_t(w), C._Bool(enable))
+}
+
+// SetTransparent determines whether the WebView window has a transparent background.
+func (w Window) SetTransparent(enable bool) {
+	C.webui_set_transparent(C.size_t(w), C._Bool(enable))
+}
+
+// SetResizable determines whether the WebView window can be resized.
+func (w Window) SetResizable(enable bool) {
+	C.webui_set_resizable(C.size_t(w), C._Bool(enable))
+}
+
+// BrowserExists checks if a web browser is installed.
+func BrowserExists(browser Browser) bool {
+	return bool(C.webui_browser_exist(C.size_t(browser)))
+}
+
+// GetBestBrowser returns the recommended web browser to use for the window.
+// It returns `NoBrowser` if no supported web browser is installed.
+func (w Window) GetBestBrowser() Browser {
+	return Browser(C.webui_get_best_browser(C.size_t(w)))
+}
+
+// SetBrowserFolder sets a custom folder in which WebUI looks for the web browser executable,
+// e.g., to use a portable Chromium shipped with the application. It applies to all windows.
+func SetBrowserFolder(path string) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	C.webui_set_browser_folder(cpath)
+}
+
+// SetCustomBrowser makes the window use the web browser in the folder `path` with the
+// additional command line arguments `args`. Needs to be called before `Show()`.
+// As WebUI supports a single custom browser folder, `path` applies to all windows.
+func (w Window) SetCustomBrowser(path string, args []string) {
+	SetBrowserFolder(path)
+	w.SetBrowserArgs(args...)
+}
+
+// SetBrowserArgs sets additional command line arguments the web browser of the window is
+// started with, e.g., `--disable-gpu` or `--window-size=800,600`. Needs to be called
+// before `Show()`. The arguments are joined by spaces, so an argument that contains
+// spaces needs to be quoted.
+func (w Window) SetBrowserArgs(args ...string) {
+	st := w.state()
+	st.mu.Lock()
+	st.browserArgs = args
+	st.mu.Unlock()
+	w.applyBrowserArgs()
+}
+
+// applyBrowserArgs passes the browser arguments of the window to WebUI.
+func (w Window) applyBrowserArgs() {
+	st := w.state()
+	st.mu.RLock()
+	args := st.browserArgs
+	if st.devToolsPort != 0 {
+		args = append(args[:len(args):len(args)], fmt.Sprintf("--remote-debugging-port=%d", st.devToolsPort))
+	}
+	st.mu.RUnlock()
+	cparams := C.CString(strings.Join(args, " "))
+	defer C.free(unsafe.Pointer(cparams))
+	C.webui_set_custom_parameters(C.size_t(w), cparams)
+}
+
 // SetKiosk determines whether Kiosk mode (full screen) is enabled for the window.
 func (w Window) SetKiosk(enable bool) {
 	C.webui_set_kiosk(C.size_t(w), C._Bool(enable))
 }
 
+// Minimize minimizes the WebView window.
+func (w Window) Minimize() {
+	C.webui_minimize(C.size_t(w))
+}
+
+// Maximize maximizes the WebView window.
+func (w Window) Maximize() {
+	C.webui_maximize(C.size_t(w))
+}
+
+// Restore restores the minimized or maximized WebView window to its previous size and position.
+// It is currently only supported on Windows. On other platforms, and if the window is not
+// shown, an error is logged.
+func (w Window) Restore() {
+	if err := w.restore(); err != nil {
+		logf(LogError, "failed to restore window %d: %v", w, err)
+	}
+}
+
+// SetAlwaysOnTop determines whether the WebView window stays on top of other windows.
+// It is currently only supported on Windows. On other platforms, and if the window is not
+// shown, an error is logged.
+func (w Window) SetAlwaysOnTop(enable bool) {
+	if err := w.setAlwaysOnTop(enable); err != nil {
+		logf(LogError, "failed to set window %d always on top: %v", w, err)
+	}
+}
+
+// SetFullscreen determines whether the window is shown in full screen.
+// WebUI provides full screen through the web browser's Kiosk mode, so this is equivalent to `SetKiosk`.
+func (w Window) SetFullscreen(enable bool) {
+	w.SetKiosk(enable)
+}
+
 // Wait waits until all opened windows get closed.
 func Wait() {
 	C.webui_wait()
+	waitShutdown()
+}
+
+// WaitContext waits until all opened windows get closed or the context is done.
+// If the context is done first, all windows are closed using `Exit()` and the context's error
+// is returned. Call `Clean()` afterwards to free all memory resources.
+func WaitContext(ctx context.Context) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			Exit()
+		case <-stop:
+		}
+	}()
+	Wait()
+	return ctx.Err()
 }
 
-// Close closes the window. The window object will still exist.
+// Close closes the window and its child windows and stops their crash watchdogs. The window
+// objects will still exist.
 func (w Window) Close() {
-	C.webui_close(C.size_t(w))
+	w.state().stopWatchdog()
+	w.close()
+}
+
+// close closes the window and its child windows without stopping the crash watchdog.
+func (w Window) close() {
+	for _, child := range w.children() {
+		child.Close()
+	}
+	w.driver().close()
+}
+
+func (d webuiDriver) close() {
+	C.webui_close(C.size_t(d))
 }
 
-// Destroy closes the window and free all memory resources.
+// Destroy closes the window and its child windows and free all memory resources.
+// All callbacks, hooks and settings of the window are removed.
 func (w Window) Destroy() {
-	C.webui_destroy(C.size_t(w))
+	w.state().stopWatchdog()
+	for _, child := range w.children() {
+		child.Destroy()
+	}
+	w.driver().destroy()
+	w.resetState()
 }
 
-// Exit closes all open windows. `Wait()` will return (Break).
+func (d webuiDriver) destroy() {
+	C.webui_destroy(C.size_t(d))
+}
+
+// Exit closes all open windows, child windows before their parents, and removes all bound
+// callbacks. `Wait()` will return (Break).
 func Exit() {
+	all := windowStates()
+	for _, st := range all {
+		st.stopWatchdog()
+	}
+	// Close child windows before their parents.
+	for w := range all {
+		if w.parent() == 0 {
+			w.close()
+		}
+	}
 	C.webui_exit()
+	for _, st := range all {
+		st.mu.Lock()
+		st.callbacks = make(map[uint]func(Event) any)
+		st.mu.Unlock()
+		st.closeSinks()
+	}
 }
 
 // SetRootFolder sets the web-server root folder path for the window.
-func (w Window) SetRootFolder(path string) {
+// Files shown by name, e.g., `Show("index.html")`, are resolved against it. Relative paths
+// depend on the working directory, which is unpredictable for applications started from a
+// file manager. Prefer absolute paths, e.g., based on `os.Executable()`.
+func (w Window) SetRootFolder(path string) (err error) {
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
-	C.webui_set_root_folder(C.size_t(w), cpath)
+	if !C.webui_set_root_folder(C.size_t(w), cpath) {
+		err = errors.New("error: failed to set the root folder")
+		return
+	}
+	w.setRootFolder(path)
+	return
 }
 
 // SetRootFolder sets the web-server root folder path for all windows.
@@ -219,23 +590,46 @@ func (w Window) SetRootFolder(path string) {
 func SetRootFolder(path string) {
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
-	C.webui_set_default_root_folder(cpath)
+	if C.webui_set_default_root_folder(cpath) {
+		setDefaultRootFolder(path)
+	}
 }
 
 // SetDefaultRootFolder sets the web-server root folder path for all windows.
+// See `Window.SetRootFolder` about relative paths.
 func SetDefaultRootFolder(path string) (err error) {
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
 	if !C.webui_set_default_root_folder(cpath) {
 		err = errors.New("error: failed to set the default root folder")
+		return
+	}
+	setDefaultRootFolder(path)
+	return
+}
+
+// EnableHotReload serves the files of all windows from `dir` and automatically reloads the
+// connected windows when a file in it changes. It is intended for development and should be
+// called before `Show()`.
+func EnableHotReload(dir string) (err error) {
+	if err = SetDefaultRootFolder(dir); err != nil {
+		return
 	}
+	C.webui_set_config(C.folder_monitor, C._Bool(true))
 	return
 }
 
 // IsShown checks if the window it's still running.
 func (w Window) IsShown() bool {
-	status := C.webui_is_shown(C.size_t(w))
-	return bool(status)
+	return w.driver().isShown()
+}
+
+func (d webuiDriver) newWindow() Window {
+	return NewWindow()
+}
+
+func (d webuiDriver) isShown() bool {
+	return bool(C.webui_is_shown(C.size_t(d)))
 }
 
 // SetTimeout sets the maximum time in seconds to wait for the browser to start.
@@ -243,7 +637,8 @@ func SetTimeout(seconds uint) {
 	C.webui_set_timeout(C.size_t(seconds))
 }
 
-// SetIcon sets the default embedded HTML favicon.
+// SetIcon sets the default embedded HTML favicon. `icon` is the text of the icon, e.g., an
+// SVG image, and `icon_type` its MIME type. Use `SetIconData` for binary images, e.g., PNG.
 func (w Window) SetIcon(icon string, icon_type string) {
 	cicon := C.CString(icon)
 	cicon_type := C.CString(icon_type)
@@ -257,7 +652,7 @@ func Encode(str string) string {
 	cstr := C.CString(str)
 	defer C.free(unsafe.Pointer(cstr))
 	encoded := C.webui_encode(cstr)
-	defer C.free(unsafe.Pointer(encoded))
+	defer C.webui_free(unsafe.Pointer(encoded))
 	return C.GoString(encoded)
 }
 
@@ -266,13 +661,20 @@ func Decode(str string) string {
 	cstr := C.CString(str)
 	defer C.free(unsafe.Pointer(cstr))
 	decoded := C.webui_decode(cstr)
-	defer C.free(unsafe.Pointer(decoded))
+	defer C.webui_free(unsafe.Pointer(decoded))
 	return C.GoString(decoded)
 }
 
 // SetHide determines whether the window is run in hidden mode.
+// Deprecated: use SetHidden instead
 func (w Window) SetHide(status bool) {
-	C.webui_set_hide(C.size_t(w), C._Bool(status))
+	w.SetHidden(status)
+}
+
+// SetHidden determines whether the window is run in hidden mode.
+// A hidden window can be shown once its content has loaded, e.g., when handling the `Connected` event.
+func (w Window) SetHidden(hidden bool) {
+	C.webui_set_hide(C.size_t(w), C._Bool(hidden))
 }
 
 // SetSize sets the window size.
@@ -285,9 +687,20 @@ func (w Window) SetPosition(x uint, y uint) {
 	C.webui_set_position(C.size_t(w), C.uint(x), C.uint(y))
 }
 
+// SetMinimumSize sets the window minimum size.
+func (w Window) SetMinimumSize(width uint, height uint) {
+	C.webui_set_minimum_size(C.size_t(w), C.uint(width), C.uint(height))
+}
+
+// Center centers the window on the screen. Works better with WebView.
+// Needs to be called before `Show()` for better results.
+func (w Window) Center() {
+	C.webui_set_center(C.size_t(w))
+}
+
 // SetProfile sets the web browser profile to use.
 // An empty `name` and `path` means the default user profile.
-// Needs to be called before `webui_show()`.
+// Needs to be called before `Show()`. Use `DeleteProfile()` to remove the profile folder again.
 func (w Window) SetProfile(name string, path string) {
 	cname := C.CString(name)
 	cpath := C.CString(path)
@@ -304,7 +717,13 @@ func (w Window) SetProxy(proxyServer string) {
 }
 
 // GetUrl returns the full current URL
+// Deprecated: use GetURL instead
 func (w Window) GetUrl() string {
+	return w.GetURL()
+}
+
+// GetURL returns the full current URL of the window's web-server, e.g., to open it on another device.
+func (w Window) GetURL() string {
 	return C.GoString(C.webui_get_url(C.size_t(w)))
 }
 
@@ -320,6 +739,13 @@ func (w Window) Navigate(url string) {
 	C.webui_navigate(C.size_t(w), curl)
 }
 
+// OpenURL opens the URL in the native default web browser.
+func OpenURL(url string) {
+	curl := C.CString(url)
+	defer C.free(unsafe.Pointer(curl))
+	C.webui_open_url(curl)
+}
+
 // Clean frees all memory resources. It should only be called at the end.
 func Clean() {
 	C.webui_clean()
@@ -340,54 +766,175 @@ func (w Window) GetParentProcessID() uint64 {
 	return uint64(C.webui_get_parent_process_id(C.size_t(w)))
 }
 
-// GetParentProcessID returns the ID of the last child process.
+// GetChildProcessID returns the ID of the last child process.
 func (w Window) GetChildProcessID() uint64 {
 	return uint64(C.webui_get_child_process_id(C.size_t(w)))
 }
 
+// GetWindowHandle returns the native handle of the window, e.g., the `HWND` on Windows, to
+// customize it with platform APIs. It returns 0 if the handle is not available, e.g., because
+// the window is not shown yet.
+func (w Window) GetWindowHandle() uintptr {
+	return uintptr(C.webui_get_hwnd(C.size_t(w)))
+}
+
 // SetPort sets a custom web-server network port to be used by WebUI.
-func (w Window) SetPort(port uint) bool {
-	return bool(C.webui_set_port(C.size_t(w), C.size_t(port)))
+// Needs to be called before `Show()`.
+func (w Window) SetPort(port uint) (err error) {
+	if !C.webui_set_port(C.size_t(w), C.size_t(port)) {
+		err = fmt.Errorf("error: failed to set port %d", port)
+	}
+	return
+}
+
+// GetPort returns the network port of the window's web-server.
+func (w Window) GetPort() uint {
+	return uint(C.webui_get_port(C.size_t(w)))
 }
 
 // == Javascript ==============================================================
 
 // Run executes JavaScript without waiting for the response.
 func (w Window) Run(script string) {
+	w.driver().run(script)
+}
+
+func (d webuiDriver) run(script string) {
 	cscript := C.CString(script)
 	defer C.free(unsafe.Pointer(cscript))
-	C.webui_run(C.size_t(w), cscript)
+	C.webui_run(C.size_t(d), cscript)
+	countBytesSent(Window(d), len(script))
 }
 
-// Script executes JavaScript and returns the response (Make sure the response buffer can hold the response).
-// The default BufferSize is 8KiB.
-func (w Window) Script(script string, options ScriptOptions) (resp string, err error) {
-	opts := ScriptOptions{
-		Timeout:    options.Timeout,
-		BufferSize: options.BufferSize,
-	}
-	if options.BufferSize == 0 {
-		opts.BufferSize = (1024 * 8)
+// SendRaw sends binary data to the JavaScript function `function` in the UI.
+// The function receives the data as `Uint8Array`, e.g., `function myFunc(bytes) { ... }`.
+func (w Window) SendRaw(function string, data []byte) {
+	if len(data) == 0 {
+		return
 	}
+	cfunction := C.CString(function)
+	defer C.free(unsafe.Pointer(cfunction))
+	C.webui_send_raw(C.size_t(w), cfunction, unsafe.Pointer(&data[0]), C.size_t(len(data)))
+	countBytesSent(w, len(data))
+}
 
-	// Create a local buffer to hold the response
-	buffer := make([]byte, uint64(opts.BufferSize))
+// Script executes JavaScript and returns the response.
+// Without a BufferSize, the response buffer starts at 8KiB. If the response doesn't fit, the
+// script is run again with a buffer of the response's size, up to 64MiB, so scripts with side
+// effects that return large responses should set a BufferSize that fits. Responses exceeding a
+// set BufferSize are truncated.
+func (w Window) Script(script string, options ScriptOptions) (resp string, err error) {
+	return w.driver().script(script, options)
+}
 
-	// Create a pointer to the local buffer
-	ptr := (*C.char)(unsafe.Pointer(&buffer[0]))
+func (d webuiDriver) script(script string, options ScriptOptions) (resp string, err error) {
+	w := Window(d)
+	bufferSize := options.BufferSize
+	grow := bufferSize == 0
+	run := script
+	if grow {
+		bufferSize = defaultScriptBufferSize
+		// Prefix the response with its size to tell whether it fits the buffer.
+		run = sizedScript(script)
+	}
 
-	cscript := C.CString(script)
+	cscript := C.CString(run)
 	defer C.free(unsafe.Pointer(cscript))
 
-	// Run the script and wait for the response
-	ok := C.webui_script(C.size_t(w), cscript, C.size_t(opts.Timeout), ptr, C.size_t(uint64(opts.BufferSize)))
+	for {
+		// Create a local buffer to hold the response
+		buffer := make([]byte, uint64(bufferSize))
+
+		// Create a pointer to the local buffer
+		ptr := (*C.char)(unsafe.Pointer(&buffer[0]))
+
+		// Run the script and wait for the response
+		start := time.Now()
+		ok := bool(C.webui_script(C.size_t(w), cscript, C.size_t(options.Timeout), ptr, C.size_t(uint64(bufferSize))))
+		recordScriptLatency(w, time.Since(start))
+		countBytesSent(w, len(run))
+		respLen := scriptResponseLen(buffer)
+		resp = string(buffer[:respLen])
+		if !ok {
+			err = fmt.Errorf("error: failed to run script: %s.\n", script)
+			return
+		}
+		if !grow {
+			return
+		}
+		var size int
+		if resp, size, ok = parseSizedResponse(resp); !ok || size == len(resp) || bufferSize == maxScriptBufferSize {
+			return
+		}
+		// The response size, its prefix, and the NUL terminator.
+		bufferSize = min(uint(respLen-len(resp)+size+1), maxScriptBufferSize)
+	}
+}
+
+// scriptResponseLen returns the length of the NUL-terminated response in the buffer.
+func scriptResponseLen(buffer []byte) int {
+	if respLen := bytes.IndexByte(buffer, 0); respLen >= 0 {
+		return respLen
+	}
+	return len(buffer)
+}
+
+// sizedScript wraps the script so that its response is prefixed with the response's size in
+// bytes, e.g., `5:hello`.
+func sizedScript(script string) string {
+	return "const r = (function () {\n" + script + "\n})();\n" +
+		"const sized = (v) => { const s = String(v); return new TextEncoder().encode(s).length + ':' + s; };\n" +
+		"return r instanceof Promise ? r.then(sized) : sized(r);"
+}
+
+// parseSizedResponse splits the response of a script wrapped with `sizedScript` into the
+// response, which may be truncated, and its full size.
+func parseSizedResponse(sized string) (resp string, size int, ok bool) {
+	prefix, resp, ok := strings.Cut(sized, ":")
 	if !ok {
-		err = fmt.Errorf("error: failed to run script: %s.\n", script)
+		return sized, 0, false
+	}
+	size, err := strconv.Atoi(prefix)
+	if err != nil || size < len(resp) {
+		return sized, 0, false
 	}
-	respLen := bytes.IndexByte(buffer[:], 0)
-	resp = string(buffer[:respLen])
+	return resp, size, true
+}
 
-	return
+// ScriptContext executes JavaScript and returns the response like `Script`.
+// If the context has a deadline, it is used as the script timeout, otherwise the script times
+// out after 30 seconds. If the context is done before the response is received, the context's
+// error is returned without waiting any longer. This does not stop the JavaScript, and WebUI
+// keeps waiting for its response in the background until the script times out.
+func (w Window) ScriptContext(ctx context.Context, script string) (resp string, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	// A zero timeout makes WebUI wait for the response forever.
+	opts := ScriptOptions{Timeout: defaultScriptContextTimeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.Timeout = uint(max(math.Ceil(time.Until(deadline).Seconds()), 1))
+	}
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case r := <-w.ScriptAsync(script, opts):
+		if r.Err != nil && ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return r.Response, r.Err
+	}
+}
+
+// ScriptAsync executes JavaScript like `Script` without blocking. The result is delivered
+// on the returned channel once the response is received. Multiple scripts can be in flight.
+func (w Window) ScriptAsync(script string, options ScriptOptions) <-chan ScriptResult {
+	result := make(chan ScriptResult, 1)
+	go func() {
+		resp, err := w.Script(script, options)
+		result <- ScriptResult{resp, err}
+	}()
+	return result
 }
 
 // SetRuntime sets the runtime for .js and .ts files to Deno and Nodejs.
@@ -395,6 +942,31 @@ func (w Window) SetRuntime(runtime Runtime) {
 	C.webui_set_runtime(C.size_t(w), C.size_t(runtime))
 }
 
+// String returns the data as string.
+func (d Data) String() string {
+	return string(d)
+}
+
+// Int parses the data as integer.
+func (d Data) Int() (int, error) {
+	return strconv.Atoi(strings.TrimSpace(string(d)))
+}
+
+// Float parses the data as float.
+func (d Data) Float() (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(string(d)), 64)
+}
+
+// Bool parses the data as boolean.
+func (d Data) Bool() (bool, error) {
+	return strconv.ParseBool(strings.TrimSpace(string(d)))
+}
+
+// Unmarshal decodes the data as JSON into the value pointed to by `v`.
+func (d Data) Unmarshal(v any) error {
+	return json.Unmarshal([]byte(d), v)
+}
+
 func (e *noArgError) Error() string {
 	return fmt.Sprintf("`%s` did not receive an argument.", e.element)
 }
@@ -403,75 +975,195 @@ func (e *getArgError) Error() string {
 	return fmt.Sprintf("error: failed to get argument of type `%s` for `%s`: %v", e.typ, e.element, e.err)
 }
 
+// cStruct returns the C representation of the event.
+// The caller must free its `element` after use.
 func (e Event) cStruct() *C.webui_event_t {
 	return &C.webui_event_t{
-		window:       C.size_t(e.Window),
-		event_type:   C.size_t(e.EventType),
-		element:      C.CString(e.Element),
-		event_number: C.size_t(e.eventNumber),
-		bind_id:      C.size_t(e.bindId),
+		window:        C.size_t(e.Window),
+		event_type:    C.size_t(e.EventType),
+		element:       C.CString(e.Element),
+		event_number:  C.size_t(e.eventNumber),
+		bind_id:       C.size_t(e.bindId),
+		client_id:     C.size_t(e.ClientID),
+		connection_id: C.size_t(e.connectionId),
 	}
 }
 
 // GetSize returns the size of the first JavaScript argument.
 func (e Event) GetSize() uint {
-	cEvent := e.cStruct()
-	defer C.free(unsafe.Pointer(cEvent.element))
-	return uint(C.webui_get_size(cEvent))
+	return e.GetSizeAt(0)
 }
 
 // GetSize returns the size of the JavaScript at the specified index.
 func (e Event) GetSizeAt(idx uint) uint {
-	cEvent := e.cStruct()
+	return e.arguments().size(idx)
+}
+
+// RawData returns the first JavaScript argument as binary data, e.g., when a `Uint8Array` was passed.
+func (e Event) RawData() []byte {
+	return e.RawDataAt(0)
+}
+
+// RawDataAt returns the JavaScript argument at the specified index as binary data.
+func (e Event) RawDataAt(idx uint) []byte {
+	return e.arguments().raw(idx)
+}
+
+// ArgCount returns the number of JavaScript arguments the event received.
+func (e Event) ArgCount() uint {
+	return e.arguments().count()
+}
+
+// GetArg returns the raw JavaScript argument at the specified index.
+func (e Event) GetArg(idx uint) Data {
+	return Data(e.GetStringAt(idx))
+}
+
+// GetString returns the first JavaScript argument as string.
+func (e Event) GetString() string {
+	return e.GetStringAt(0)
+}
+
+// GetStringAt returns the JavaScript argument at the specified index as string.
+func (e Event) GetStringAt(idx uint) string {
+	return e.arguments().str(idx)
+}
+
+// GetInt returns the first JavaScript argument as integer.
+func (e Event) GetInt() int {
+	return e.GetIntAt(0)
+}
+
+// GetIntAt returns the JavaScript argument at the specified index as integer.
+func (e Event) GetIntAt(idx uint) int {
+	return e.arguments().integer(idx)
+}
+
+// GetFloat returns the first JavaScript argument as float.
+func (e Event) GetFloat() float64 {
+	return e.GetFloatAt(0)
+}
+
+// GetFloatAt returns the JavaScript argument at the specified index as float.
+func (e Event) GetFloatAt(idx uint) float64 {
+	return e.arguments().float(idx)
+}
+
+// GetBool returns the first JavaScript argument as boolean.
+func (e Event) GetBool() bool {
+	return e.GetBoolAt(0)
+}
+
+// GetBoolAt returns the JavaScript argument at the specified index as boolean.
+func (e Event) GetBoolAt(idx uint) bool {
+	return e.arguments().boolean(idx)
+}
+
+// webuiArgs reads the JavaScript arguments of an event from WebUI, which keeps them until the
+// event is handled.
+type webuiArgs Event
+
+func (a webuiArgs) count() uint {
+	cEvent := Event(a).cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	return uint(C.webui_get_count(cEvent))
+}
+
+func (a webuiArgs) size(idx uint) uint {
+	cEvent := Event(a).cStruct()
 	defer C.free(unsafe.Pointer(cEvent.element))
 	return uint(C.webui_get_size_at(cEvent, C.size_t(idx)))
 }
 
+func (a webuiArgs) raw(idx uint) []byte {
+	cEvent := Event(a).cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	cIdx := C.size_t(idx)
+	size := C.webui_get_size_at(cEvent, cIdx)
+	if size == 0 {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(C.webui_get_string_at(cEvent, cIdx)), C.int(size))
+}
+
+func (a webuiArgs) str(idx uint) string {
+	cEvent := Event(a).cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	return C.GoString(C.webui_get_string_at(cEvent, C.size_t(idx)))
+}
+
+func (a webuiArgs) integer(idx uint) int {
+	cEvent := Event(a).cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	return int(C.webui_get_int_at(cEvent, C.size_t(idx)))
+}
+
+func (a webuiArgs) float(idx uint) float64 {
+	cEvent := Event(a).cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	return float64(C.webui_get_float_at(cEvent, C.size_t(idx)))
+}
+
+func (a webuiArgs) boolean(idx uint) bool {
+	cEvent := Event(a).cStruct()
+	defer C.free(unsafe.Pointer(cEvent.element))
+	return bool(C.webui_get_bool_at(cEvent, C.size_t(idx)))
+}
+
 // GetArg parses the JavaScript argument into a Go data type.
 func GetArg[T any](e Event) (arg T, err error) {
-	cEvent := e.cStruct()
-	defer C.free(unsafe.Pointer(cEvent.element))
-	if uint(C.webui_get_size(cEvent)) == 0 {
+	return GetArgAt[T](e, 0)
+}
+
+// GetArgAt parses the JavaScript argument with the specified index into a Go data type.
+// An error is returned if the argument does not match the type, e.g., if a JavaScript object
+// is passed for a string or a number. The argument as read by WebUI is returned with it.
+func GetArgAt[T any](e Event, idx uint) (arg T, err error) {
+	if e.GetSizeAt(idx) == 0 {
 		err = &noArgError{e.Element}
 	}
+	raw := e.GetStringAt(idx)
 	var ret T
 	switch p := any(&ret).(type) {
 	case *string:
-		*p = C.GoString(C.webui_get_string(cEvent))
+		*p = raw
 	case *int:
-		*p = int(C.webui_get_int(cEvent))
+		*p = e.GetIntAt(idx)
 	case *bool:
-		*p = bool(C.webui_get_bool(cEvent))
+		*p = e.GetBoolAt(idx)
 	default:
-		if jsonErr := json.Unmarshal([]byte(C.GoString(C.webui_get_string(cEvent))), p); err != nil {
+		if jsonErr := json.Unmarshal([]byte(raw), p); jsonErr != nil && err == nil {
 			err = &getArgError{jsonErr, e.Element, reflect.TypeOf(ret).String()}
 		}
 	}
+	if err == nil {
+		if kindErr := checkArgKind(any(&ret), raw); kindErr != nil {
+			err = &getArgError{kindErr, e.Element, reflect.TypeOf(ret).String()}
+		}
+	}
 	arg = ret
 	return
 }
 
-// GetArgAt parses the JavaScript argument with the specified index into a Go data type.
-func GetArgAt[T any](e Event, idx uint) (arg T, err error) {
-	cEvent := e.cStruct()
-	defer C.free(unsafe.Pointer(cEvent.element))
-	cIdx := C.size_t(idx)
-	if uint(C.webui_get_size_at(cEvent, cIdx)) == 0 {
-		err = &noArgError{e.Element}
-	}
-	var ret T
-	switch p := any(&ret).(type) {
-	case *string:
-		*p = C.GoString(C.webui_get_string_at(cEvent, cIdx))
+// checkArgKind returns an error if the raw JavaScript argument does not match the
+// kind of the Go value `p` points to. WebUI's C getters fall back to zero values for
+// mismatching input (e.g., an object passed where a number is expected), which would
+// otherwise go unnoticed.
+func checkArgKind(p any, raw string) (err error) {
+	switch p.(type) {
 	case *int:
-		*p = int(C.webui_get_int_at(cEvent, cIdx))
+		_, err = strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
 	case *bool:
-		*p = bool(C.webui_get_bool_at(cEvent, cIdx))
-	default:
-		if jsonErr := json.Unmarshal([]byte(C.GoString(C.webui_get_string_at(cEvent, cIdx))), p); err != nil {
-			err = &getArgError{jsonErr, e.Element, reflect.TypeOf(ret).String()}
+		if raw != "true" && raw != "false" {
+			err = fmt.Errorf("invalid boolean %q", raw)
+		}
+	case *string:
+		// WebUI passes JavaScript objects and arrays as JSON, which needs a struct, map or
+		// slice type to be decoded into.
+		if trimmed := strings.TrimSpace(raw); (strings.HasPrefix(trimmed, "{") ||
+			strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+			err = fmt.Errorf("invalid string %q: got a JSON object or array", raw)
 		}
 	}
-	arg = ret
 	return
 }
@@ -0,0 +1,116 @@
+package webui
+
+import "testing"
+
+func TestScriptResponseLen(t *testing.T) {
+	tests := []struct {
+		name    string
+		buffer  []byte
+		wantLen int
+	}{
+		{"no terminator", []byte("abcd"), 4},
+		{"terminator at end", []byte("abc\x00"), 3},
+		{"terminator inside", []byte("ab\x00\x00"), 2},
+		{"empty response", []byte("\x00\x00\x00\x00"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scriptResponseLen(tt.buffer); got != tt.wantLen {
+				t.Errorf("scriptResponseLen(%q) = %d; want %d", tt.buffer, got, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestParseSizedResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		sized    string
+		wantResp string
+		wantSize int
+		wantOk   bool
+	}{
+		{"complete", "5:hello", "hello", 5, true},
+		{"exactly filled", "3:abc", "abc", 3, true},
+		{"truncated", "11:hello", "hello", 11, true},
+		{"empty response", "0:", "", 0, true},
+		{"colon in response", "3:a:b", "a:b", 3, true},
+		{"no prefix", "hello", "hello", 0, false},
+		{"invalid prefix", "x:hello", "x:hello", 0, false},
+		{"size below length", "2:hello", "2:hello", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, size, ok := parseSizedResponse(tt.sized)
+			if resp != tt.wantResp || size != tt.wantSize || ok != tt.wantOk {
+				t.Errorf("parseSizedResponse(%q) = %q, %d, %v; want %q, %d, %v",
+					tt.sized, resp, size, ok, tt.wantResp, tt.wantSize, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestMapResponseSortedKeys(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	w.Bind("info", func(e Event) any {
+		return map[string]any{"zeta": 1, "alpha": true, "mu": "m", "beta": nil, "omega": []int{2}}
+	})
+	result, err := w.Call("info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"alpha":true,"beta":null,"mu":"m","omega":[2],"zeta":1}`
+	for i := 0; i < 10; i++ {
+		if got := string(responseJSON(result)); got != want {
+			t.Fatalf("responseJSON() = %s; want %s", got, want)
+		}
+	}
+}
+
+func TestCheckArgKind(t *testing.T) {
+	var (
+		i int
+		b bool
+		s string
+	)
+	tests := []struct {
+		name    string
+		p       any
+		raw     string
+		wantErr bool
+	}{
+		{"int", &i, "42", false},
+		{"negative int", &i, " -7 ", false},
+		{"object for int", &i, `{"a":1}`, true},
+		{"decimal for int", &i, "1.5", true},
+		{"empty for int", &i, "", true},
+		{"true", &b, "true", false},
+		{"false", &b, "false", false},
+		{"yes for bool", &b, "yes", true},
+		{"number for bool", &b, "1", true},
+		{"text for string", &s, "hello", false},
+		{"number for string", &s, "42", false},
+		{"brace text for string", &s, "{hello}", false},
+		{"object for string", &s, `{"a":1}`, true},
+		{"array for string", &s, ` [1, 2]`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkArgKind(tt.p, tt.raw); (err != nil) != tt.wantErr {
+				t.Errorf("checkArgKind(%T, %q) error = %v; want error %v", tt.p, tt.raw, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Encode and Decode allocate their results in C. Run the benchmark with a large -benchtime and
+// watch the process memory to check that they are released.
+func BenchmarkEncodeDecode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if Decode(Encode("Hello, WebUI!")) != "Hello, WebUI!" {
+			b.Fatal("round trip changed the string")
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package webui
+
+/*
+#cgo CFLAGS: -Iwebui/include
+#include "webui.h"
+
+extern void goWebuiLogger(size_t level, char* log, void* user_data);
+static void go_webui_set_logger() {
+	webui_set_logger((void (*)(size_t, const char*, void*))goWebuiLogger, NULL);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+type LogLevel uint8
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogError
+)
+
+// Logger receives the log messages of go-webui and of the WebUI library.
+type Logger interface {
+	Log(level LogLevel, msg string)
+}
+
+// LoggerFunc is a function that implements Logger.
+type LoggerFunc func(level LogLevel, msg string)
+
+type stdLogger struct{}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger = stdLogger{}
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	default:
+		return "error"
+	}
+}
+
+func (f LoggerFunc) Log(level LogLevel, msg string) {
+	f(level, msg)
+}
+
+func (stdLogger) Log(level LogLevel, msg string) {
+	log.Printf("%s: %s\n", level, msg)
+}
+
+func (s slogLogger) Log(level LogLevel, msg string) {
+	slevel := slog.LevelError
+	switch level {
+	case LogDebug:
+		slevel = slog.LevelDebug
+	case LogInfo:
+		slevel = slog.LevelInfo
+	}
+	s.l.Log(context.Background(), slevel, msg)
+}
+
+// SlogLogger returns a Logger that writes to the structured logging handler `h`.
+func SlogLogger(h slog.Handler) Logger {
+	return slogLogger{slog.New(h)}
+}
+
+// SetLogger sets the logger that receives the log messages of go-webui and of the WebUI
+// library, replacing the default output to the standard logger. The WebUI library only
+// logs when built with the `webui_log` build tag. A nil logger restores the default.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = stdLogger{}
+	}
+	loggerMu.Lock()
+	logger = l
+	loggerMu.Unlock()
+	C.go_webui_set_logger()
+}
+
+func logf(level LogLevel, format string, args ...any) {
+	loggerMu.RLock()
+	l := logger
+	loggerMu.RUnlock()
+	l.Log(level, fmt.Sprintf(format, args...))
+}
+
+// Private function that receives the log messages of the WebUI library.
+//
+//export goWebuiLogger
+func goWebuiLogger(level C.size_t, msg *C.char, _ unsafe.Pointer) {
+	logf(LogLevel(level), "%s", strings.TrimRight(C.GoString(msg), "\n"))
+}
@@ -0,0 +1,27 @@
+package webui
+
+// Middleware wraps the handling of an event. It calls `next` to continue with the next
+// middleware or the bound callback, or returns without calling it to stop the event.
+type Middleware func(e Event, next func(Event) any) any
+
+// Use adds a middleware that wraps every callback bound to the window. Middlewares run in the
+// order they were added, the first one being the outermost.
+func (w Window) Use(middleware Middleware) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.middlewares = append(st.middlewares, middleware)
+}
+
+// chain wraps `callback` in the middlewares of the window.
+func (w Window) chain(callback func(Event) any) func(Event) any {
+	st := w.state()
+	st.mu.RLock()
+	mws := st.middlewares
+	st.mu.RUnlock()
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw, next := mws[i], callback
+		callback = func(e Event) any { return mw(e, next) }
+	}
+	return callback
+}
@@ -0,0 +1,210 @@
+package webui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// MockWindow is a window for testing bound callbacks without a web browser. Events are
+// dispatched with `Call`, and scripts the callbacks run are recorded instead of being sent
+// to a UI. Showing the window delivers a `Connected` event, closing it a `Disconnected` event.
+//
+// Binding, unbinding, `Show`, `ShowModal`, `IsShown`, `Close`, `Destroy` and running scripts
+// with `Run`, `Script` and their variants are supported. Other window functions must not be
+// used with mock windows. Close mock windows when the test is done, e.g., with `t.Cleanup(w.Close)`.
+type MockWindow struct {
+	Window
+	mu      sync.Mutex
+	bindIds map[string]uint
+	scripts []string
+	handler func(script string) (string, error)
+	shown   bool
+}
+
+var (
+	mocksMu sync.Mutex
+	// Mock windows are numbered downwards from the highest window number so they don't
+	// collide with WebUI windows.
+	nextMockWindow = ^Window(0)
+)
+
+// NewMockWindow creates a mock window for tests.
+func NewMockWindow() *MockWindow {
+	mocksMu.Lock()
+	defer mocksMu.Unlock()
+	m := &MockWindow{Window: nextMockWindow, bindIds: make(map[string]uint)}
+	nextMockWindow--
+	m.Window.resetState()
+	st := m.Window.state()
+	st.mu.Lock()
+	st.driver = m
+	st.mu.Unlock()
+	return m
+}
+
+// Close closes the mock window and its child windows like `Destroy`, and releases them.
+func (m *MockWindow) Close() {
+	m.Window.Destroy()
+}
+
+// destroy closes the mock window. Its window number must not be used afterwards.
+func (m *MockWindow) destroy() {
+	m.close()
+}
+
+// newWindow creates a mock window, so that windows opened by mock windows are mocked too.
+func (m *MockWindow) newWindow() Window {
+	return NewMockWindow().Window
+}
+
+func (m *MockWindow) isShown() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.shown
+}
+
+// show marks the window as shown and delivers a `Connected` event.
+func (m *MockWindow) show(content string) bool {
+	m.mu.Lock()
+	m.shown = true
+	m.mu.Unlock()
+	m.lifecycle(Connected)
+	return true
+}
+
+// close marks the window as closed and delivers a `Disconnected` event if it was shown.
+func (m *MockWindow) close() {
+	m.mu.Lock()
+	shown := m.shown
+	m.shown = false
+	m.mu.Unlock()
+	if shown {
+		m.lifecycle(Disconnected)
+	}
+}
+
+// lifecycle delivers a lifecycle event to the hooks and the callback bound to all events.
+func (m *MockWindow) lifecycle(eventType EventType) {
+	e := Event{Window: m.Window, EventType: eventType, args: storedArgs{}}
+	e.bindId = m.bindId("")
+	if e.bindId == 0 {
+		return
+	}
+	m.deliver(e)
+}
+
+// deliver calls the hooks and the callback bound to the event like the Go event handler.
+func (m *MockWindow) deliver(e Event) (result any, ok bool) {
+	if e.EventType == Disconnected {
+		defer dropSession(e.Window, e.ClientID)
+	}
+	runHooks(e)
+	callback, ok := getCallback(m.Window, e.bindId)
+	if !ok {
+		return nil, false
+	}
+	return callCallback(m.chain(callback), e), true
+}
+
+// bind returns the bind ID of the element, like WebUI does for real windows.
+func (m *MockWindow) bind(element string) uint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, ok := m.bindIds[element]
+	if !ok {
+		id = uint(len(m.bindIds) + 1)
+		m.bindIds[element] = id
+	}
+	return id
+}
+
+// unbind returns the bind ID of the element, like WebUI does for real windows.
+func (m *MockWindow) unbind(element string) uint {
+	return m.bindId(element)
+}
+
+// bindId returns the bind ID of the element, or `0` if it is not bound.
+func (m *MockWindow) bindId(element string) uint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bindIds[element]
+}
+
+// Call dispatches a callback event for `element` with the JavaScript arguments `args`, like
+// calling `element(...args)` in the UI, and returns the callback's result. Strings and
+// byte slices are passed as they are, other arguments are encoded as JSON. An error is
+// returned if no callback is bound to the element, or if the callback returned an error
+// or panicked.
+func (m *MockWindow) Call(element string, args ...any) (result any, err error) {
+	stored := make(storedArgs, len(args))
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case string:
+			stored[i] = a
+		case []byte:
+			stored[i] = string(a)
+		default:
+			raw, jsonErr := json.Marshal(a)
+			if jsonErr != nil {
+				return nil, fmt.Errorf("error: failed to encode argument %d for `%s`: %v", i, element, jsonErr)
+			}
+			stored[i] = string(raw)
+		}
+	}
+	e := Event{Window: m.Window, EventType: Callback, Element: element, args: stored}
+	// WebUI passes the event to the binding of all events as well.
+	if allEventsId := m.bindId(""); allEventsId != 0 && element != "" {
+		all := e
+		all.bindId = allEventsId
+		m.deliver(all)
+	}
+	e.bindId = m.bindId(element)
+	result, ok := m.deliver(e)
+	if e.bindId == 0 || !ok {
+		return nil, fmt.Errorf("error: no callback is bound to `%s`", element)
+	}
+	if callbackErr, ok := result.(error); ok {
+		return nil, callbackErr
+	}
+	return result, nil
+}
+
+// Scripts returns the scripts run in the window, in the order they were run.
+func (m *MockWindow) Scripts() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.scripts...)
+}
+
+// ResetScripts clears the recorded scripts.
+func (m *MockWindow) ResetScripts() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scripts = nil
+}
+
+// SetScriptHandler sets the function that answers scripts run with `Script`. Without a
+// handler, scripts respond with an empty string.
+func (m *MockWindow) SetScriptHandler(handler func(script string) (string, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handler = handler
+}
+
+// run records the script.
+func (m *MockWindow) run(script string) {
+	m.script(script, ScriptOptions{})
+}
+
+// script records the script and returns the response of the script handler.
+func (m *MockWindow) script(script string, options ScriptOptions) (string, error) {
+	m.mu.Lock()
+	m.scripts = append(m.scripts, script)
+	handler := m.handler
+	m.mu.Unlock()
+	if handler == nil {
+		return "", nil
+	}
+	return handler(script)
+}
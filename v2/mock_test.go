@@ -0,0 +1,85 @@
+package webui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMockWindowCall(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	w.Bind("greet", func(e Event) any {
+		name, err := GetArg[string](e)
+		if err != nil {
+			return err
+		}
+		e.Window.Run("greeted()")
+		return "Hello, " + name
+	})
+
+	result, err := w.Call("greet", "Go")
+	if err != nil || result != "Hello, Go" {
+		t.Fatalf(`Call("greet", "Go") = %v, %v; want "Hello, Go", nil`, result, err)
+	}
+	if scripts := w.Scripts(); len(scripts) != 1 || scripts[0] != "greeted()" {
+		t.Errorf("Scripts() = %q; want [\"greeted()\"]", scripts)
+	}
+	if _, err := w.Call("greet"); err == nil {
+		t.Error(`Call("greet") without argument returned no error`)
+	}
+	if _, err := w.Call("unbound"); err == nil {
+		t.Error(`Call("unbound") returned no error`)
+	}
+}
+
+func TestMockWindowLifecycle(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	var events []EventType
+	w.OnConnected(func() { events = append(events, Connected) })
+	w.OnDisconnected(func() { events = append(events, Disconnected) })
+
+	w.Show("index.html")
+	if !w.IsShown() {
+		t.Error("IsShown() = false after Show")
+	}
+	w.Window.Close()
+	if w.IsShown() {
+		t.Error("IsShown() = true after Close")
+	}
+	if len(events) != 2 || events[0] != Connected || events[1] != Disconnected {
+		t.Errorf("events = %v; want [Connected Disconnected]", events)
+	}
+}
+
+// mockWindow returns the mock window with the number `w`, if it is one.
+func mockWindow(w Window) (m *MockWindow, ok bool) {
+	statesMu.RLock()
+	st, ok := states[w]
+	statesMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	m, ok = st.driver.(*MockWindow)
+	return
+}
+
+func TestMockWindowClose(t *testing.T) {
+	w := NewMockWindow()
+	w.Bind("fail", func(e Event) any { return errors.New("failed") })
+	if _, err := w.Call("fail"); err == nil || err.Error() != "failed" {
+		t.Errorf(`Call("fail") error = %v; want "failed"`, err)
+	}
+	w.Close()
+	if _, ok := mockWindow(w.Window); ok {
+		t.Error("mock window is still registered after Close")
+	}
+	statesMu.RLock()
+	_, ok := states[w.Window]
+	statesMu.RUnlock()
+	if ok {
+		t.Error("state of mock window is kept after Close")
+	}
+}
@@ -0,0 +1,59 @@
+package webui
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Injected into the parent window to block interaction with it while a modal is open.
+const modalOverlayScript = `(() => {
+	if (document.getElementById("%[1]s")) return;
+	const overlay = document.createElement("div");
+	overlay.id = "%[1]s";
+	overlay.style.cssText = "position:fixed;inset:0;z-index:2147483647;background:rgba(0,0,0,.4);cursor:not-allowed";
+	document.body.appendChild(overlay);
+})();`
+
+// ShowModal opens embedded HTML, or a file, in a new child window and blocks interaction with
+// the window until the child is closed. The child resolves the dialog by calling
+// `webui.resolveModal(value)` in JavaScript. ShowModal then destroys the child and returns
+// `value`. If the child is closed without resolving, empty data is returned.
+func (w Window) ShowModal(content string) (result Data, err error) {
+	child := w.driver().newWindow()
+	child.SetParent(w)
+	done := make(chan Data, 1)
+	// Held by the child's callback and hook, so that the child is only destroyed once they returned.
+	var handling sync.Mutex
+	defer func() {
+		handling.Lock()
+		handling.Unlock()
+		child.Destroy()
+	}()
+	resolve := func(d Data) {
+		select {
+		case done <- d:
+		default:
+		}
+	}
+	child.Bind("resolveModal", func(e Event) any {
+		handling.Lock()
+		defer handling.Unlock()
+		value, _ := GetArg[string](e)
+		resolve(Data(value))
+		return nil
+	})
+	child.OnDisconnected(func() {
+		handling.Lock()
+		defer handling.Unlock()
+		resolve("")
+	})
+
+	overlayId := fmt.Sprintf("webui-modal-%d", child)
+	w.Run(fmt.Sprintf(modalOverlayScript, overlayId))
+	defer w.Run(fmt.Sprintf(`document.getElementById("%s")?.remove();`, overlayId))
+	if err = child.Show(content); err != nil {
+		return
+	}
+	result = <-done
+	return
+}
@@ -0,0 +1,48 @@
+package webui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShowModal(t *testing.T) {
+	parent := NewMockWindow()
+	t.Cleanup(parent.Close)
+	type modalResult struct {
+		data Data
+		err  error
+	}
+	done := make(chan modalResult)
+	go func() {
+		data, err := parent.ShowModal("dialog.html")
+		done <- modalResult{data, err}
+	}()
+
+	// Wait for the dialog to be shown.
+	var child *MockWindow
+	for deadline := time.Now().Add(5 * time.Second); child == nil || !child.IsShown(); {
+		if time.Now().After(deadline) {
+			t.Fatal("modal window was not shown")
+		}
+		time.Sleep(time.Millisecond)
+		if children := parent.children(); len(children) == 1 {
+			child, _ = mockWindow(children[0])
+		}
+	}
+	if _, err := child.Call("resolveModal", "picked.txt"); err != nil {
+		t.Fatalf(`Call("resolveModal") error = %v`, err)
+	}
+
+	res := <-done
+	if res.err != nil || res.data != "picked.txt" {
+		t.Errorf(`ShowModal() = %q, %v; want "picked.txt", nil`, res.data, res.err)
+	}
+	if _, ok := mockWindow(child.Window); ok {
+		t.Error("modal window was not destroyed")
+	}
+	scripts := parent.Scripts()
+	if len(scripts) != 2 || !strings.Contains(scripts[0], "createElement") || !strings.Contains(scripts[1], ".remove()") {
+		t.Errorf("parent scripts = %q; want the overlay to be added and removed", scripts)
+	}
+}
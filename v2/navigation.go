@@ -0,0 +1,48 @@
+package webui
+
+// NavAction is the decision of a navigation policy.
+type NavAction struct {
+	cancel   bool
+	redirect string
+}
+
+var (
+	// NavAllow lets the navigation proceed.
+	NavAllow = NavAction{}
+	// NavCancel keeps the window on the current page.
+	NavCancel = NavAction{cancel: true}
+)
+
+// NavRedirect navigates the window to `url` instead of the requested URL.
+func NavRedirect(url string) NavAction {
+	return NavAction{redirect: url}
+}
+
+// SetNavigationPolicy sets a function that decides whether navigations of the window, e.g.,
+// following a link, are allowed, canceled or redirected. E.g., to keep users inside the UI
+// and open external links in the system browser, the policy can call `OpenURL` and return
+// `NavCancel`. Callbacks bound to all events should not navigate when a policy is set.
+func (w Window) SetNavigationPolicy(policy func(url string) NavAction) {
+	w.addHook(func(h *windowHooks) { h.navPolicy = policy })
+}
+
+// applyNavPolicy performs the navigation WebUI blocked because the window's events are
+// handled in Go. Without a policy, the navigation is left to a callback bound to all events
+// if there is one, and allowed otherwise.
+func applyNavPolicy(e Event, url string, policy func(url string) NavAction) {
+	action := NavAllow
+	if policy != nil {
+		// Cancel navigations the policy failed to decide on.
+		action = NavCancel
+		callHook("navigation policy", func() { action = policy(url) })
+	} else if _, ok := getCallback(e.Window, e.bindId); ok {
+		return
+	}
+	switch {
+	case action.cancel:
+	case action.redirect != "":
+		e.Window.Navigate(action.redirect)
+	default:
+		e.Window.Navigate(url)
+	}
+}
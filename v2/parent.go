@@ -0,0 +1,40 @@
+package webui
+
+import "fmt"
+
+// SetParent makes the window a child of `parent`. Closing or destroying the parent also
+// closes or destroys its children, and `Exit` closes children before their parents. A parent
+// of `0` removes the association. An error is returned if `parent` is the window itself or
+// one of its descendants.
+func (child Window) SetParent(parent Window) error {
+	for p := parent; p != 0; p = p.parent() {
+		if p == child {
+			return fmt.Errorf("error: failed to set parent of window %d: window %d is the window or its descendant", child, parent)
+		}
+	}
+	st := child.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.parent = parent
+	return nil
+}
+
+// parent returns the parent of the window, or `0` if it has none.
+func (w Window) parent() Window {
+	st := w.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.parent
+}
+
+// children returns the windows whose parent is `w`.
+func (w Window) children() (children []Window) {
+	for child, st := range windowStates() {
+		st.mu.RLock()
+		if st.parent == w {
+			children = append(children, child)
+		}
+		st.mu.RUnlock()
+	}
+	return
+}
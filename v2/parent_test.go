@@ -0,0 +1,40 @@
+package webui
+
+import "testing"
+
+func TestCloseParentClosesChildren(t *testing.T) {
+	parent, child1, child2 := NewMockWindow(), NewMockWindow(), NewMockWindow()
+	t.Cleanup(parent.Close)
+	child1.SetParent(parent.Window)
+	child2.SetParent(parent.Window)
+	for _, w := range []*MockWindow{parent, child1, child2} {
+		w.Show("index.html")
+	}
+
+	parent.Window.Close()
+	for _, w := range []*MockWindow{parent, child1, child2} {
+		if w.IsShown() {
+			t.Errorf("window %d is shown after closing the parent", w.Window)
+		}
+	}
+}
+
+func TestSetParentRejectsDescendant(t *testing.T) {
+	root, child, grandchild := NewMockWindow(), NewMockWindow(), NewMockWindow()
+	t.Cleanup(root.Close)
+	child.SetParent(root.Window)
+	grandchild.SetParent(child.Window)
+
+	if err := root.SetParent(grandchild.Window); err == nil {
+		t.Error("SetParent(grandchild) returned no error")
+	}
+	if p := root.parent(); p != 0 {
+		t.Errorf("parent of root window = %d; want 0", p)
+	}
+	if err := root.SetParent(root.Window); err == nil {
+		t.Error("SetParent(itself) returned no error")
+	}
+	if p := root.parent(); p != 0 {
+		t.Errorf("window is its own parent")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package webui
+
+import "syscall"
+
+// processAlive reports whether a process with the given ID is running. Tests replace it to
+// simulate crashed processes.
+var processAlive = func(pid uint64) bool {
+	err := syscall.Kill(int(pid), 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,21 @@
+package webui
+
+import "syscall"
+
+// Exit code reported by `GetExitCodeProcess` for processes that are still running.
+const stillActive = 259
+
+// processAlive reports whether a process with the given ID is running. Tests replace it to
+// simulate crashed processes.
+var processAlive = func(pid uint64) bool {
+	h, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
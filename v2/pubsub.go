@@ -0,0 +1,95 @@
+package webui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Maximum number of messages queued for a window while it is not connected.
+// When the queue is full, the oldest messages are dropped.
+const publishQueueSize = 1024
+
+// Defines `webui.subscribe(topic, callback)`, which returns a function to unsubscribe.
+const subscribeScript = `if (typeof webui !== "undefined" && !webui.subscribe) {
+	webui.subscribe = (topic, callback) => {
+		const listener = (e) => callback(e.detail);
+		window.addEventListener("webui:" + topic, listener);
+		return () => window.removeEventListener("webui:" + topic, listener);
+	};
+}`
+
+// Delivers a message to the subscribers of a topic.
+const publishScript = `window.dispatchEvent(new CustomEvent("webui:" + %s, { detail: %s }));`
+
+// publisher queues the messages of a window until it is connected.
+type publisher struct {
+	mu        sync.Mutex
+	connected bool
+	queue     []string
+}
+
+// Publish sends `payload` encoded as JSON to the subscribers of `topic` in the UI.
+// Messages published while the window is loading or reconnecting are queued and delivered
+// once it is connected. In JavaScript, subscribe to a topic with:
+//
+//	const unsubscribe = webui.subscribe("progress", (payload) => { ... });
+//
+// `webui.subscribe` is available once the window is connected. Before that, e.g., while the
+// page loads, listen to the `webui:<topic>` event of `window` instead; the payload is its `detail`.
+func (w Window) Publish(topic string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error: failed to encode payload of topic `%s` into JSON: %w", topic, err)
+	}
+	ctopic, _ := json.Marshal(topic)
+	script := fmt.Sprintf(publishScript, ctopic, data)
+
+	p := w.publisher()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.connected {
+		if len(p.queue) == publishQueueSize {
+			p.queue = p.queue[1:]
+		}
+		p.queue = append(p.queue, script)
+		return nil
+	}
+	w.Run(script)
+	return nil
+}
+
+// publisher returns the publisher of the window, setting it up on first use.
+func (w Window) publisher() *publisher {
+	st := w.state()
+	st.mu.Lock()
+	p, created := st.publisher, st.publisher == nil
+	if created {
+		p = &publisher{connected: w.IsShown()}
+		st.publisher = p
+	}
+	st.mu.Unlock()
+	if !created {
+		return p
+	}
+	w.OnConnected(func() {
+		w.Run(subscribeScript)
+		// Flush while locked, so that queued messages are delivered before new ones.
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for _, script := range p.queue {
+			w.Run(script)
+		}
+		p.queue = nil
+		p.connected = true
+	})
+	w.OnDisconnected(func() {
+		p.mu.Lock()
+		p.connected = false
+		p.mu.Unlock()
+	})
+	if p.connected {
+		w.Run(subscribeScript)
+	}
+	return p
+}
@@ -0,0 +1,54 @@
+package webui
+
+import "sync"
+
+// Session stores values of a client across events. It is safe for concurrent use.
+type Session struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// Session returns the session of the client that sent the event. The session lives until
+// the client disconnects, after the event handlers of the disconnection ran.
+func (e Event) Session() *Session {
+	// Receive disconnection events to clean up sessions.
+	e.Window.addHook(func(h *windowHooks) {})
+	st := e.Window.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.sessions[e.ClientID]
+	if !ok {
+		s = &Session{values: make(map[string]any)}
+		st.sessions[e.ClientID] = s
+	}
+	return s
+}
+
+// Get returns the value stored for `key`, or nil if there is none.
+func (s *Session) Get(key string) any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[key]
+}
+
+// Set stores the value for `key`.
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Delete removes the value stored for `key`.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// dropSession removes the session of the client.
+func dropSession(w Window, clientID uint) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.sessions, clientID)
+}
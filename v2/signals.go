@@ -0,0 +1,63 @@
+package webui
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	shutdownMu    sync.Mutex
+	shutdownHooks []func()
+	handleSignals sync.Once
+	// Closed once the shutdown hooks ran, nil until a handled signal is received. Keeps
+	// `Wait()` from returning before.
+	shutdownDone chan struct{}
+)
+
+// OnShutdown registers a function that is called when the application is shut down by a
+// signal handled with `HandleSignals`. Hooks are called in the order they were registered,
+// after all windows were closed.
+func OnShutdown(hook func()) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	shutdownHooks = append(shutdownHooks, hook)
+}
+
+// HandleSignals makes SIGINT (Ctrl-C) and SIGTERM close all windows using `Exit()` and call
+// the `OnShutdown` hooks. `Wait()` returns once the hooks are done. A second signal
+// terminates the application immediately. Calling it more than once has no effect.
+func HandleSignals() {
+	handleSignals.Do(func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			signal.Stop(sig)
+			done := make(chan struct{})
+			defer close(done)
+			// Set before `Exit()` makes `Wait()` return.
+			shutdownMu.Lock()
+			shutdownDone = done
+			shutdownMu.Unlock()
+			Exit()
+			shutdownMu.Lock()
+			hooks := shutdownHooks
+			shutdownMu.Unlock()
+			for _, hook := range hooks {
+				hook()
+			}
+		}()
+	})
+}
+
+// waitShutdown waits until the shutdown hooks ran if a handled signal was received.
+func waitShutdown() {
+	shutdownMu.Lock()
+	done := shutdownDone
+	shutdownMu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
@@ -0,0 +1,101 @@
+package webui
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// windowState is the Go side state of a window. It is discarded when the window is destroyed
+// or created again, so that a reused window number starts out clean.
+type windowState struct {
+	mu sync.RWMutex
+	// Performs the operations that depend on the UI.
+	driver driver
+	// User Go callback functions by bind ID.
+	callbacks map[uint]func(Event) any
+	// Bind IDs of the bound elements.
+	bindIds map[string]uint
+	// Lifecycle hooks, nil until the first hook is added.
+	hooks *windowHooks
+	// Types of the functions bound with BindFunc and BindStruct.
+	funcTypes   map[string]reflect.Type
+	middlewares []Middleware
+	parent      Window
+	// Connection IDs of the connected clients.
+	clients  map[uint]uint
+	sessions map[uint]*Session
+	// Handler of close requests of the WebView window.
+	closeHandler func() bool
+	// Go handler serving the files of the window.
+	fileHandler http.Handler
+	// Root folder of the window, as set with `SetRootFolder`.
+	rootFolder   string
+	browserArgs  []string
+	devToolsPort uint
+	// Content the window was last successfully shown with.
+	lastShown *shownContent
+	// Stop channel of the running crash watchdog.
+	watchdog       chan struct{}
+	watchdogHooked bool
+	// Whether the UI disconnected while the browser process was still running.
+	closedByUser bool
+	publisher    *publisher
+	meta         *windowMeta
+	// Time the callbacks took to handle their events, by element.
+	latencies map[string]*latencyHistogram
+	counters  windowCounters
+}
+
+var (
+	statesMu sync.RWMutex
+	states   = make(map[Window]*windowState)
+)
+
+// state returns the state of the window, creating it on first use.
+func (w Window) state() *windowState {
+	statesMu.RLock()
+	st, ok := states[w]
+	statesMu.RUnlock()
+	if ok {
+		return st
+	}
+	statesMu.Lock()
+	defer statesMu.Unlock()
+	if st, ok = states[w]; !ok {
+		st = &windowState{
+			driver:    webuiDriver(w),
+			callbacks: make(map[uint]func(Event) any),
+			bindIds:   make(map[string]uint),
+			funcTypes: make(map[string]reflect.Type),
+			clients:   make(map[uint]uint),
+			sessions:  make(map[uint]*Session),
+			latencies: make(map[string]*latencyHistogram),
+		}
+		states[w] = st
+	}
+	return st
+}
+
+// resetState discards the state of the window and stops its crash watchdog.
+func (w Window) resetState() {
+	statesMu.Lock()
+	st, ok := states[w]
+	delete(states, w)
+	statesMu.Unlock()
+	if ok {
+		st.stopWatchdog()
+		st.closeSinks()
+	}
+}
+
+// windowStates returns the states of all windows.
+func windowStates() map[Window]*windowState {
+	statesMu.RLock()
+	defer statesMu.RUnlock()
+	all := make(map[Window]*windowState, len(states))
+	for w, st := range states {
+		all[w] = st
+	}
+	return all
+}
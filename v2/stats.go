@@ -0,0 +1,171 @@
+package webui
+
+import (
+	"expvar"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// Number of linear sub-buckets per power of two in a latency histogram.
+// Recorded values are accurate to within 1/histSubBuckets (~6%).
+const histSubBuckets = 16
+
+// latencyHistogram is a log-linear (HDR-style) histogram of microsecond latencies.
+type latencyHistogram struct {
+	counts [(64 - 3) * histSubBuckets]uint64
+	total  uint64
+}
+
+// Counters of the traffic between a window and its UI.
+type windowCounters struct {
+	events    uint64
+	bytesSent uint64
+	script    latencyHistogram
+}
+
+// RuntimeStats is a snapshot of the runtime metrics of all windows.
+type RuntimeStats struct {
+	Windows map[Window]WindowStats
+}
+
+// WindowStats holds the runtime metrics of a window.
+type WindowStats struct {
+	// Number of clients connected to the window.
+	Connections int
+	// Number of events received from the UI.
+	EventsHandled uint64
+	// Number of bytes of scripts, binary data and callback responses sent to the UI.
+	BytesSent uint64
+	// Time `Script` took to receive responses.
+	ScriptLatency LatencyStats
+	// Time the callbacks took to handle their events, by element.
+	Callbacks map[string]LatencyStats
+}
+
+// LatencyStats summarizes recorded latencies.
+type LatencyStats struct {
+	Count         uint64
+	P50, P95, P99 time.Duration
+}
+
+func histBucket(us uint64) int {
+	if us < 2*histSubBuckets {
+		return int(us)
+	}
+	shift := bits.Len64(us) - 5
+	return (shift+1)*histSubBuckets + int(us>>shift) - histSubBuckets
+}
+
+// histBucketMax returns the highest microsecond value that falls into the bucket `idx`.
+func histBucketMax(idx int) uint64 {
+	if idx < 2*histSubBuckets {
+		return uint64(idx)
+	}
+	shift := idx/histSubBuckets - 1
+	sub := uint64(idx%histSubBuckets + histSubBuckets)
+	return (sub+1)<<shift - 1
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	us := d.Microseconds()
+	if us < 0 {
+		us = 0
+	}
+	h.counts[histBucket(uint64(us))]++
+	h.total++
+}
+
+// percentile returns the latency below which the fraction `q` of the recorded values fall.
+func (h *latencyHistogram) percentile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(q * float64(h.total)))
+	var seen uint64
+	for i, n := range h.counts {
+		seen += n
+		if n > 0 && seen >= target {
+			return time.Duration(histBucketMax(i)) * time.Microsecond
+		}
+	}
+	return 0
+}
+
+func recordLatency(w Window, element string, d time.Duration) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	h, ok := st.latencies[element]
+	if !ok {
+		h = &latencyHistogram{}
+		st.latencies[element] = h
+	}
+	h.record(d)
+}
+
+// LatencyPercentiles returns the 50th, 95th and 99th percentile of the time the callbacks
+// bound to `element` took to handle their events. It returns zeros if no event was handled yet.
+func (w Window) LatencyPercentiles(element string) (p50, p95, p99 time.Duration) {
+	st := w.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	h, ok := st.latencies[element]
+	if !ok {
+		return
+	}
+	return h.percentile(0.50), h.percentile(0.95), h.percentile(0.99)
+}
+
+func (h *latencyHistogram) stats() LatencyStats {
+	return LatencyStats{h.total, h.percentile(0.50), h.percentile(0.95), h.percentile(0.99)}
+}
+
+func countEvent(w Window) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.counters.events++
+}
+
+func countBytesSent(w Window, n int) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.counters.bytesSent += uint64(n)
+}
+
+func recordScriptLatency(w Window, d time.Duration) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.counters.script.record(d)
+}
+
+// Stats returns a snapshot of the runtime metrics of all windows. Metrics of destroyed
+// windows are discarded.
+func Stats() RuntimeStats {
+	stats := RuntimeStats{Windows: make(map[Window]WindowStats)}
+	for w, st := range windowStates() {
+		st.mu.RLock()
+		ws := WindowStats{
+			Connections:   len(st.clients),
+			EventsHandled: st.counters.events,
+			BytesSent:     st.counters.bytesSent,
+			ScriptLatency: st.counters.script.stats(),
+			Callbacks:     make(map[string]LatencyStats, len(st.latencies)),
+		}
+		for element, h := range st.latencies {
+			ws.Callbacks[element] = h.stats()
+		}
+		st.mu.RUnlock()
+		stats.Windows[w] = ws
+	}
+	return stats
+}
+
+// PublishExpvar publishes the runtime metrics as the expvar variable `name`, e.g., to
+// serve them at `/debug/vars`. Like `expvar.Publish`, it panics if the name is already used.
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return Stats() }))
+}
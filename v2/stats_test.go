@@ -0,0 +1,47 @@
+package webui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyPercentiles(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	if p50, p95, p99 := w.LatencyPercentiles("slow"); p50 != 0 || p95 != 0 || p99 != 0 {
+		t.Errorf("LatencyPercentiles() without events = %v, %v, %v; want zeros", p50, p95, p99)
+	}
+	// 1ms to 1000ms, in random order.
+	for i := 0; i < 1000; i++ {
+		recordLatency(w.Window, "slow", time.Duration(i*7919%1000+1)*time.Millisecond)
+	}
+
+	p50, p95, p99 := w.LatencyPercentiles("slow")
+	for _, tt := range []struct {
+		name      string
+		got, want time.Duration
+	}{
+		{"p50", p50, 500 * time.Millisecond},
+		{"p95", p95, 950 * time.Millisecond},
+		{"p99", p99, 990 * time.Millisecond},
+	} {
+		if diff := tt.got - tt.want; diff < 0 || diff > tt.want/histSubBuckets {
+			t.Errorf("%s = %v; want %v within %d%%", tt.name, tt.got, tt.want, 100/histSubBuckets)
+		}
+	}
+	if count := Stats().Windows[w.Window].Callbacks["slow"].Count; count != 1000 {
+		t.Errorf("Stats() count = %d; want 1000", count)
+	}
+}
+
+func TestHistBucket(t *testing.T) {
+	for _, us := range []uint64{0, 1, 31, 32, 33, 1000, 123456, 1 << 40} {
+		idx := histBucket(us)
+		if hi := histBucketMax(idx); hi < us || hi-us > us/histSubBuckets {
+			t.Errorf("histBucketMax(histBucket(%d)) = %d", us, hi)
+		}
+		if idx > 0 && histBucketMax(idx-1) >= us {
+			t.Errorf("value %d also fits into bucket %d", us, idx-1)
+		}
+	}
+}
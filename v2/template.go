@@ -0,0 +1,32 @@
+package webui
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// ShowTemplate executes the template with `data` and shows the result like `Show` does with
+// embedded HTML. If the window is already open, it will be refreshed. As with any embedded
+// HTML, the template has to include `<script src="webui.js"></script>`.
+func (w Window) ShowTemplate(t *template.Template, data any) error {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return fmt.Errorf("error: failed to execute template: %w", err)
+	}
+	return w.Show(sb.String())
+}
+
+// RenderTemplate executes the template with `data` and replaces the content of the elements
+// matching the CSS selector with the result, without reloading the page.
+func (w Window) RenderTemplate(selector string, t *template.Template, data any) error {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return fmt.Errorf("error: failed to execute template: %w", err)
+	}
+	cselector, _ := json.Marshal(selector)
+	chtml, _ := json.Marshal(sb.String())
+	w.Run(fmt.Sprintf(`document.querySelectorAll(%s).forEach((el) => el.innerHTML = %s);`, cselector, chtml))
+	return nil
+}
@@ -0,0 +1,39 @@
+// Code generated by go-webui. DO NOT EDIT.
+
+export interface Point {
+	Lat: number;
+	Lng: number;
+}
+
+export interface image_Point {
+	X: number;
+	Y: number;
+}
+
+export interface User {
+	name: string;
+	age: number;
+	address: Address | null;
+	tags?: string[];
+	created: string;
+}
+
+export interface Address {
+	street: string;
+	city?: string;
+}
+
+export declare const backend: {
+	math: {
+		add(arg0: number, arg1: number): Promise<number>;
+	};
+	ping(): Promise<void>;
+	users: {
+		Bounds(arg0: Point): Promise<image_Point>;
+		Get(arg0: number): Promise<User>;
+		List(): Promise<Record<string, (User | null)[]>>;
+		Locate(arg0: image_Point): Promise<Point>;
+		Raw(arg0: string, arg1: number | null): Promise<void>;
+		Save(arg0: User): Promise<void>;
+	};
+};
@@ -0,0 +1,16 @@
+// Code generated by go-webui. DO NOT EDIT.
+
+export const backend = {
+	math: {
+		add: (...args) => webui.call("math.add", ...args),
+	},
+	ping: (...args) => webui.call("ping", ...args),
+	users: {
+		Bounds: (...args) => webui.call("users.Bounds", ...args),
+		Get: (...args) => webui.call("users.Get", ...args),
+		List: (...args) => webui.call("users.List", ...args),
+		Locate: (...args) => webui.call("users.Locate", ...args),
+		Raw: (...args) => webui.call("users.Raw", ...args),
+		Save: (...args) => webui.call("users.Save", ...args),
+	},
+};
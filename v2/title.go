@@ -0,0 +1,81 @@
+package webui
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Replaces the page's favicons with a data URL.
+const iconScript = `{
+	document.querySelectorAll("link[rel~='icon']").forEach((l) => l.remove());
+	const link = document.createElement("link");
+	link.rel = "icon";
+	link.href = %s;
+	document.head.appendChild(link);
+}`
+
+// Title and icon applied to the UI of a window on each connection.
+type windowMeta struct {
+	mu         sync.Mutex
+	title      *string
+	iconScript string
+}
+
+// SetTitle sets the title of the UI, i.e., `document.title`, on all connected clients and
+// on clients that connect later, e.g., after navigating to another page.
+func (w Window) SetTitle(title string) {
+	w.updateMeta(func(m *windowMeta) { m.title = &title })
+	w.Run(titleScript(title))
+}
+
+// SetIconData sets the favicon of the UI from the image data `icon` with the MIME type
+// `mimeType`, e.g., `image/png` or `image/svg+xml`. SVG icons are served by WebUI like with
+// `SetIcon`. Other images can't be served by WebUI, so they are set as favicon of the
+// page on each connection, replacing the page's own favicons.
+func (w Window) SetIconData(icon []byte, mimeType string) {
+	if strings.HasPrefix(mimeType, "image/svg") {
+		w.SetIcon(string(icon), mimeType)
+		return
+	}
+	dataURL, _ := json.Marshal("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(icon))
+	script := fmt.Sprintf(iconScript, dataURL)
+	w.updateMeta(func(m *windowMeta) { m.iconScript = script })
+	w.Run(script)
+}
+
+func titleScript(title string) string {
+	ctitle, _ := json.Marshal(title)
+	return fmt.Sprintf("document.title = %s;", ctitle)
+}
+
+// updateMeta applies `update` to the title and icon of the window. On first use, it registers
+// applying them on connection.
+func (w Window) updateMeta(update func(m *windowMeta)) {
+	st := w.state()
+	st.mu.Lock()
+	m, created := st.meta, st.meta == nil
+	if created {
+		m = &windowMeta{}
+		st.meta = m
+	}
+	st.mu.Unlock()
+	if created {
+		w.OnConnected(func() {
+			m.mu.Lock()
+			title, iconScript := m.title, m.iconScript
+			m.mu.Unlock()
+			if title != nil {
+				w.Run(titleScript(*title))
+			}
+			if iconScript != "" {
+				w.Run(iconScript)
+			}
+		})
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	update(m)
+}
@@ -0,0 +1,318 @@
+package webui
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Header of generated files.
+const generatedHeader = "// Code generated by go-webui. DO NOT EDIT.\n"
+
+var (
+	jsIdentifier      = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	nonIdentifierRune = regexp.MustCompile(`[^A-Za-z0-9_$]+`)
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// bindingNode is a level of the object tree of bound functions.
+// Elements like `api.Save` are nested as `api: { Save }`.
+type bindingNode struct {
+	funcs    map[string]reflect.Type
+	children map[string]*bindingNode
+	elements map[string]string
+}
+
+// tsTypes collects the TypeScript interfaces of named Go structs.
+type tsTypes struct {
+	names map[reflect.Type]string
+	// Whether an interface name is taken.
+	taken map[string]bool
+	decls []string
+}
+
+func newBindingNode() *bindingNode {
+	return &bindingNode{
+		funcs:    make(map[string]reflect.Type),
+		children: make(map[string]*bindingNode),
+		elements: make(map[string]string),
+	}
+}
+
+// bindingTree returns the object tree of the functions bound with BindFunc and BindStruct.
+func (w Window) bindingTree() *bindingNode {
+	st := w.state()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	root := newBindingNode()
+	for element, t := range st.funcTypes {
+		node := root
+		path := strings.Split(element, ".")
+		for _, name := range path[:len(path)-1] {
+			child, ok := node.children[name]
+			if !ok {
+				child = newBindingNode()
+				node.children[name] = child
+			}
+			node = child
+		}
+		node.funcs[path[len(path)-1]] = t
+		node.elements[path[len(path)-1]] = element
+	}
+	return root
+}
+
+func (n *bindingNode) keys() []string {
+	keys := make([]string, 0, len(n.funcs)+len(n.children))
+	for k := range n.funcs {
+		keys = append(keys, k)
+	}
+	for k := range n.children {
+		if _, ok := n.funcs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsKey(name string) string {
+	if jsIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// WriteTypeScript writes TypeScript declarations of the functions bound with `BindFunc` and
+// `BindStruct` to `out`. The functions are declared as members of the object `name`, which
+// is exported by the client written with `WriteJSClient`.
+func (w Window) WriteTypeScript(out io.Writer, name string) error {
+	types := &tsTypes{names: make(map[reflect.Type]string), taken: make(map[string]bool)}
+	var body strings.Builder
+	w.bindingTree().writeTypeScript(&body, types, "")
+	var sb strings.Builder
+	sb.WriteString(generatedHeader)
+	for _, decl := range types.decls {
+		sb.WriteString("\n" + decl)
+	}
+	fmt.Fprintf(&sb, "\nexport declare const %s: {\n%s};\n", name, body.String())
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func (n *bindingNode) writeTypeScript(sb *strings.Builder, types *tsTypes, indent string) {
+	indent += "\t"
+	for _, key := range n.keys() {
+		if t, ok := n.funcs[key]; ok {
+			fmt.Fprintf(sb, "%s%s(%s): Promise<%s>;\n", indent, jsKey(key), types.params(t), types.result(t))
+			continue
+		}
+		fmt.Fprintf(sb, "%s%s: {\n", indent, jsKey(key))
+		n.children[key].writeTypeScript(sb, types, indent)
+		fmt.Fprintf(sb, "%s};\n", indent)
+	}
+}
+
+// WriteJSClient writes a JavaScript module to `out` that exports the object `name`, whose
+// members call the functions bound with `BindFunc` and `BindStruct`, e.g., `await name.save(user)`.
+func (w Window) WriteJSClient(out io.Writer, name string) error {
+	var sb strings.Builder
+	sb.WriteString(generatedHeader)
+	fmt.Fprintf(&sb, "\nexport const %s = {\n", name)
+	w.bindingTree().writeJSClient(&sb, "")
+	sb.WriteString("};\n")
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func (n *bindingNode) writeJSClient(sb *strings.Builder, indent string) {
+	indent += "\t"
+	for _, key := range n.keys() {
+		if element, ok := n.elements[key]; ok {
+			fmt.Fprintf(sb, "%s%s: (...args) => webui.call(%q, ...args),\n", indent, jsKey(key), element)
+			continue
+		}
+		fmt.Fprintf(sb, "%s%s: {\n", indent, jsKey(key))
+		n.children[key].writeJSClient(sb, indent)
+		fmt.Fprintf(sb, "%s},\n", indent)
+	}
+}
+
+// GenerateClient writes the JavaScript client `<name>.js` and its TypeScript declarations
+// `<name>.d.ts` for the window's bound functions into the directory `dir`. To keep them in
+// sync with the Go code, call it from the application when it is run by `go generate`, e.g.:
+//
+//	//go:generate go run . -generate-client
+//
+//	if slices.Contains(os.Args, "-generate-client") {
+//		w.GenerateClient("ui", "backend")
+//		return
+//	}
+func (w Window) GenerateClient(dir string, name string) (err error) {
+	for ext, write := range map[string]func(io.Writer, string) error{
+		".js":   w.WriteJSClient,
+		".d.ts": w.WriteTypeScript,
+	} {
+		var f *os.File
+		if f, err = os.Create(filepath.Join(dir, name+ext)); err != nil {
+			return
+		}
+		err = write(f, name)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// params returns the TypeScript parameter list of the JavaScript arguments of the function.
+func (types *tsTypes) params(t reflect.Type) string {
+	var params []string
+	for i := 0; i < t.NumIn(); i++ {
+		if i == 0 && t.In(i) == eventType {
+			continue
+		}
+		params = append(params, fmt.Sprintf("arg%d: %s", len(params), types.of(t.In(i))))
+	}
+	return strings.Join(params, ", ")
+}
+
+// result returns the TypeScript type the function's call resolves with.
+func (types *tsTypes) result(t reflect.Type) string {
+	if t.NumOut() == 0 || t.Out(0) == errorType {
+		return "void"
+	}
+	return types.of(t.Out(0))
+}
+
+// of returns the TypeScript type of the JSON encoding of the Go type.
+func (types *tsTypes) of(t reflect.Type) string {
+	switch {
+	case t == timeType, t.Implements(textMarshalerType) && !t.Implements(jsonMarshalerType):
+		return "string"
+	case t.Implements(jsonMarshalerType):
+		return "any"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// Encoded as base64.
+			return "string"
+		}
+		elem := types.of(t.Elem())
+		if strings.Contains(elem, "|") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + types.of(t.Elem()) + ">"
+	case reflect.Pointer:
+		return types.of(t.Elem()) + " | null"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return types.object(t, "")
+		}
+		return types.named(t)
+	}
+	return "any"
+}
+
+// named returns the name of the TypeScript interface of the named struct type,
+// declaring it on first use.
+func (types *tsTypes) named(t reflect.Type) string {
+	if name, ok := types.names[t]; ok {
+		return name
+	}
+	name := types.interfaceName(t)
+	types.names[t] = name
+	types.taken[name] = true
+	// Reserve the position before the field types add their own declarations.
+	idx := len(types.decls)
+	types.decls = append(types.decls, "")
+	types.decls[idx] = fmt.Sprintf("export interface %s %s\n", name, types.object(t, ""))
+	return name
+}
+
+// interfaceName returns an unused TypeScript interface name for the named struct type. Types
+// whose name is taken by a type of another package are qualified with their package name,
+// e.g., `models_User`, and numbered if that is taken too.
+func (types *tsTypes) interfaceName(t reflect.Type) string {
+	// Type arguments of generic types aren't valid in identifiers.
+	name := strings.Trim(nonIdentifierRune.ReplaceAllString(t.Name(), "_"), "_")
+	if !types.taken[name] {
+		return name
+	}
+	qualified := nonIdentifierRune.ReplaceAllString(path.Base(t.PkgPath()), "_") + "_" + name
+	name = qualified
+	for i := 2; types.taken[name]; i++ {
+		name = fmt.Sprintf("%s%d", qualified, i)
+	}
+	return name
+}
+
+// object returns the TypeScript object type of the struct's JSON encoding.
+func (types *tsTypes) object(t reflect.Type, indent string) string {
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	types.fields(&sb, t, indent+"\t")
+	sb.WriteString(indent + "}")
+	return sb.String()
+}
+
+func (types *tsTypes) fields(sb *strings.Builder, t reflect.Type, indent string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// Fields of embedded structs are promoted in JSON.
+				types.fields(sb, ft, indent)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		optional := ""
+		if strings.Contains(","+opts+",", ",omitempty,") {
+			optional = "?"
+		}
+		typ := types.of(ft)
+		if strings.Contains(","+opts+",", ",string,") {
+			typ = "string"
+		}
+		fmt.Fprintf(sb, "%s%s%s: %s;\n", indent, jsKey(name), optional, typ)
+	}
+}
@@ -0,0 +1,107 @@
+package webui
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type User struct {
+	Name    string    `json:"name"`
+	Age     int       `json:"age"`
+	Address *Address  `json:"address"`
+	Tags    []string  `json:"tags,omitempty"`
+	Created time.Time `json:"created"`
+	secret  string
+}
+
+// Point collides with `image.Point`.
+type Point struct {
+	Lat, Lng float64
+}
+
+type userService struct{}
+
+func (userService) Get(id int) (User, error)    { return User{}, nil }
+func (userService) Save(e Event, u User) error  { return errors.New("failed") }
+func (userService) List() map[string][]*User    { return nil }
+func (userService) Locate(p image.Point) Point  { return Point{} }
+func (userService) Bounds(p Point) image.Point  { return image.Point{} }
+func (userService) Raw(data []byte, n *float64) {}
+
+// clientWindow returns a mock window with nested bindings of struct parameters and results
+// and error returns.
+func clientWindow(t *testing.T) *MockWindow {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	if err := w.BindStruct("users", userService{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.BindFunc("math.add", func(a, b int) int { return a + b }); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.BindFunc("ping", func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// checkGolden compares the output with the golden file `testdata/<name>`.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s; got:\n%s", golden, got)
+	}
+}
+
+func TestWriteTypeScript(t *testing.T) {
+	var out bytes.Buffer
+	if err := clientWindow(t).WriteTypeScript(&out, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "backend.d.ts", out.Bytes())
+}
+
+func TestWriteJSClient(t *testing.T) {
+	var out bytes.Buffer
+	if err := clientWindow(t).WriteJSClient(&out, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "backend.js", out.Bytes())
+}
+
+func TestGenerateClient(t *testing.T) {
+	dir := t.TempDir()
+	if err := clientWindow(t).GenerateClient(dir, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"backend.js", "backend.d.ts"} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, name, got)
+	}
+}
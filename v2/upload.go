@@ -0,0 +1,152 @@
+package webui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defines `webui.upload(file, chunkSize)`, which streams a `File` or `Blob` to Go in chunks.
+const uploadScript = `if (typeof webui !== "undefined" && !webui.upload) {
+	webui.upload = async (file, chunkSize = 1024 * 1024) => {
+		const id = await webui.call("__webui_upload_start", file.name ?? "");
+		const done = new Promise((resolve, reject) => { uploads.set(id, { resolve, reject }); });
+		try {
+			for (let offset = 0; offset < file.size; offset += chunkSize) {
+				const chunk = await file.slice(offset, offset + chunkSize).arrayBuffer();
+				await webui.call("__webui_upload_chunk", id, new Uint8Array(chunk));
+			}
+		} catch (e) {
+			done.catch(() => {});
+			await webui.call("__webui_upload_end", id, String(e));
+			throw e;
+		}
+		await webui.call("__webui_upload_end", id, "");
+		return done;
+	};
+	const uploads = new Map();
+	// Called by Go once the upload handler returned.
+	webui.__uploadDone = (id, err) => {
+		const up = uploads.get(id);
+		uploads.delete(id);
+		if (up) err === null ? up.resolve() : up.reject(new Error(err));
+	};
+}`
+
+// Time an upload waits for its next chunk before it is aborted.
+const uploadIdleTimeout = 30 * time.Second
+
+// upload is a file upload in progress.
+type upload struct {
+	w    *io.PipeWriter
+	done chan error
+	// Aborts the upload if the UI stops sending it.
+	idle *time.Timer
+}
+
+var (
+	uploadsMu sync.Mutex
+	uploads   = make(map[int]*upload)
+	uploadId  atomic.Int64
+)
+
+// OnUpload sets the function that receives the files uploaded from the UI. Files are streamed
+// in chunks over the WebUI connection, so their size is not limited by memory. In JavaScript,
+// upload a file, e.g., from an `<input type="file">`, with:
+//
+//	await webui.upload(input.files[0]);
+//
+// The call resolves when the handler returned, or rejects with the handler's error. Uploads
+// that receive no chunk for 30 seconds are aborted, and the handler reads an error.
+// `webui.upload` is available once the window is connected.
+func (w Window) OnUpload(handler func(name string, r io.Reader) error) {
+	w.Bind("__webui_upload_start", func(e Event) any {
+		r, pw := io.Pipe()
+		up := &upload{w: pw, done: make(chan error, 1)}
+		id := int(uploadId.Add(1))
+		up.idle = time.AfterFunc(uploadIdleTimeout, func() {
+			if up, ok := takeUpload(id); ok {
+				up.w.CloseWithError(errors.New("error: upload timed out"))
+			}
+		})
+		uploadsMu.Lock()
+		uploads[id] = up
+		uploadsMu.Unlock()
+		name := e.GetString()
+		go func() {
+			err := handler(name, r)
+			// Fail the remaining chunks if the handler stopped reading early.
+			r.CloseWithError(errors.New("upload handler returned"))
+			up.done <- err
+		}()
+		return id
+	})
+	w.Bind("__webui_upload_chunk", func(e Event) any {
+		up, err := getUpload(e.GetIntAt(0))
+		if err != nil {
+			return err
+		}
+		// Waiting for the handler to read doesn't count as idle.
+		up.idle.Stop()
+		defer up.idle.Reset(uploadIdleTimeout)
+		if _, err := up.w.Write(e.RawDataAt(1)); err != nil {
+			return err
+		}
+		return nil
+	})
+	w.Bind("__webui_upload_end", func(e Event) any {
+		id := e.GetIntAt(0)
+		up, ok := takeUpload(id)
+		if !ok {
+			return fmt.Errorf("error: unknown upload %d", id)
+		}
+		up.idle.Stop()
+		if msg := e.GetStringAt(1); msg != "" {
+			up.w.CloseWithError(errors.New(msg))
+		} else {
+			up.w.Close()
+		}
+		// Respond once the handler returned, without holding up other events.
+		clientID := e.ClientID
+		go func() {
+			result := []byte("null")
+			if err := <-up.done; err != nil {
+				result, _ = json.Marshal(err.Error())
+			}
+			if err := w.RunClient(clientID, fmt.Sprintf("webui.__uploadDone(%d, %s);", id, result)); err != nil {
+				logf(LogError, "failed to report upload %d: %v", id, err)
+			}
+		}()
+		return nil
+	})
+	w.OnConnected(func() {
+		w.Run(uploadScript)
+	})
+	if w.IsShown() {
+		w.Run(uploadScript)
+	}
+}
+
+// takeUpload removes the upload from the uploads in progress and returns it.
+func takeUpload(id int) (up *upload, ok bool) {
+	uploadsMu.Lock()
+	defer uploadsMu.Unlock()
+	if up, ok = uploads[id]; ok {
+		delete(uploads, id)
+	}
+	return
+}
+
+func getUpload(id int) (*upload, error) {
+	uploadsMu.Lock()
+	defer uploadsMu.Unlock()
+	up, ok := uploads[id]
+	if !ok {
+		return nil, fmt.Errorf("error: unknown upload %d", id)
+	}
+	return up, nil
+}
@@ -0,0 +1,112 @@
+package webui
+
+import (
+	"errors"
+	"time"
+)
+
+type shownContent struct {
+	content string
+	browser Browser
+}
+
+func setLastShown(w Window, shown shownContent) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.lastShown = &shown
+}
+
+// Restart closes the window and shows it again with the content and browser it was last shown with.
+func (w Window) Restart() error {
+	st := w.state()
+	st.mu.RLock()
+	shown := st.lastShown
+	st.mu.RUnlock()
+	if shown == nil {
+		return errors.New("error: failed to restart window that was never shown")
+	}
+	w.close()
+	switch shown.browser {
+	case AnyBrowser:
+		return w.Show(shown.content)
+	case Webview:
+		return w.ShowWebView(shown.content)
+	}
+	return w.ShowBrowser(shown.content, shown.browser)
+}
+
+// EnableCrashWatchdog checks the window's browser process every `interval` and restarts
+// the window if the process has died. An interval of zero disables the watchdog. The watchdog
+// stops when the window is closed, destroyed or exited, and doesn't restart windows whose UI
+// was closed by the user, i.e., that disconnected while the browser process was still running.
+// It uses lifecycle hooks (see `OnConnected`).
+func (w Window) EnableCrashWatchdog(interval time.Duration) {
+	st := w.state()
+	st.stopWatchdog()
+	if interval <= 0 {
+		return
+	}
+	st.mu.Lock()
+	hooked := st.watchdogHooked
+	st.watchdogHooked = true
+	stop := make(chan struct{})
+	st.watchdog = stop
+	st.mu.Unlock()
+	if !hooked {
+		w.OnConnected(func() { w.setClosedByUser(false) })
+		w.OnDisconnected(func() {
+			pid := w.GetChildProcessID()
+			w.setClosedByUser(pid != 0 && processAlive(pid))
+		})
+	}
+	go w.watchProcess(interval, stop)
+}
+
+// stopWatchdog stops the crash watchdog of the window, if it is running.
+func (st *windowState) stopWatchdog() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.watchdog != nil {
+		close(st.watchdog)
+		st.watchdog = nil
+	}
+}
+
+func (w Window) setClosedByUser(closed bool) {
+	st := w.state()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.closedByUser = closed
+}
+
+func (w Window) watchProcess(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		w.checkProcess(w.GetChildProcessID())
+	}
+}
+
+// checkProcess restarts the window if its browser process `pid` has died without the user
+// closing the UI.
+func (w Window) checkProcess(pid uint64) {
+	if pid == 0 || processAlive(pid) {
+		return
+	}
+	st := w.state()
+	st.mu.RLock()
+	closedByUser := st.closedByUser
+	st.mu.RUnlock()
+	if closedByUser {
+		return
+	}
+	if err := w.Restart(); err != nil {
+		logf(LogError, "crash watchdog failed to restart window: %v", err)
+	}
+}
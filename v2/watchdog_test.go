@@ -0,0 +1,53 @@
+package webui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckProcess(t *testing.T) {
+	alive := true
+	defer func(orig func(uint64) bool) { processAlive = orig }(processAlive)
+	processAlive = func(pid uint64) bool { return alive }
+
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	connects := 0
+	w.OnConnected(func() { connects++ })
+	w.Show("index.html")
+
+	w.checkProcess(42)
+	if connects != 1 {
+		t.Errorf("window was restarted while its process is running")
+	}
+	alive = false
+	w.checkProcess(0)
+	if connects != 1 {
+		t.Errorf("window was restarted without a process")
+	}
+	w.checkProcess(42)
+	if connects != 2 {
+		t.Errorf("window was not restarted after its process died")
+	}
+	w.setClosedByUser(true)
+	w.checkProcess(42)
+	if connects != 2 {
+		t.Errorf("window was restarted after the user closed it")
+	}
+}
+
+func TestCloseStopsWatchdog(t *testing.T) {
+	w := NewMockWindow()
+	t.Cleanup(w.Close)
+	w.EnableCrashWatchdog(time.Hour)
+	st := w.state()
+	st.mu.RLock()
+	stop := st.watchdog
+	st.mu.RUnlock()
+	w.Window.Close()
+	select {
+	case <-stop:
+	default:
+		t.Error("crash watchdog is still running after Close")
+	}
+}
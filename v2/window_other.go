@@ -0,0 +1,15 @@
+//go:build !windows
+
+package webui
+
+import "errors"
+
+var errUnsupported = errors.New("not supported on this platform")
+
+func (w Window) restore() error {
+	return errUnsupported
+}
+
+func (w Window) setAlwaysOnTop(enable bool) error {
+	return errUnsupported
+}
@@ -0,0 +1,46 @@
+package webui
+
+import (
+	"errors"
+	"syscall"
+)
+
+var (
+	user32           = syscall.NewLazyDLL("user32.dll")
+	procShowWindow   = user32.NewProc("ShowWindow")
+	procSetWindowPos = user32.NewProc("SetWindowPos")
+)
+
+const (
+	swRestore     = 9
+	hwndTopmost   = ^uintptr(0)     // -1
+	hwndNotopmost = ^uintptr(0) - 1 // -2
+	swpNoSize     = 0x0001
+	swpNoMove     = 0x0002
+)
+
+var errNoWindowHandle = errors.New("no native window handle")
+
+func (w Window) restore() error {
+	hwnd := w.GetWindowHandle()
+	if hwnd == 0 {
+		return errNoWindowHandle
+	}
+	procShowWindow.Call(hwnd, swRestore)
+	return nil
+}
+
+func (w Window) setAlwaysOnTop(enable bool) error {
+	hwnd := w.GetWindowHandle()
+	if hwnd == 0 {
+		return errNoWindowHandle
+	}
+	insertAfter := hwndNotopmost
+	if enable {
+		insertAfter = hwndTopmost
+	}
+	if ok, _, err := procSetWindowPos.Call(hwnd, insertAfter, 0, 0, 0, 0, swpNoMove|swpNoSize); ok == 0 {
+		return err
+	}
+	return nil
+}